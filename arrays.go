@@ -1,5 +1,9 @@
 package gsl
 
+import (
+	"math/rand/v2"
+)
+
 // CopySlice return a copy of |arr|.
 func CopySlice[T any](arr []T) []T {
 	ret := make([]T, len(arr))
@@ -151,3 +155,259 @@ func Map[T any, U any](
 
 	return mapped
 }
+
+// Pair is a pair of values, e.g. elements zipped by Zip.
+type Pair[T any, U any] struct {
+	First  T
+	Second U
+}
+
+// GroupBy groups elements of |arr| by their keys from |key|,
+// preserving the order of elements within each group.
+func GroupBy[T any, K comparable](arr []T, key func(elem T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, elem := range arr {
+		k := key(elem)
+		groups[k] = append(groups[k], elem)
+	}
+
+	return groups
+}
+
+// Partition splits |arr| into elements that satisfy |f| and those that do not,
+// preserving their order.
+func Partition[T any](arr []T, f func(elem T) bool) ([]T, []T) {
+	var matched, rest []T
+	for _, elem := range arr {
+		if f(elem) {
+			matched = append(matched, elem)
+			continue
+		}
+
+		rest = append(rest, elem)
+	}
+
+	return matched, rest
+}
+
+// Chunk splits |arr| into consecutive chunks of |size| elements, the last of which may be shorter.
+// The chunks are sub-slices of |arr| with capped capacity, so appending to a chunk
+// does not overwrite the next one. Chunk panics if |size| is less than 1.
+func Chunk[T any](arr []T, size int) [][]T {
+	if size < 1 {
+		panic("chunk size cannot be less than 1")
+	}
+
+	if arr == nil {
+		return nil
+	}
+
+	chunks := make([][]T, 0, (len(arr)+size-1)/size)
+	for i := 0; i < len(arr); i += size {
+		end := min(i+size, len(arr))
+		chunks = append(chunks, arr[i:end:end])
+	}
+
+	return chunks
+}
+
+// Unique returns elements of |arr| without duplicates, keeping the first occurrences in order.
+func Unique[T comparable](arr []T) []T {
+	return UniqueBy(arr, func(elem T) T { return elem })
+}
+
+// UniqueBy is like Unique, but elements are considered duplicates if their keys from |key| are equal.
+func UniqueBy[T any, K comparable](arr []T, key func(elem T) K) []T {
+	if arr == nil {
+		return nil
+	}
+
+	seen := make(map[K]struct{}, len(arr))
+	unique := make([]T, 0, len(arr))
+
+	for _, elem := range arr {
+		k := key(elem)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+
+		seen[k] = struct{}{}
+		unique = append(unique, elem)
+	}
+
+	return unique
+}
+
+// Difference returns elements of |a| that are not in |b|, in the order of |a|.
+// Duplicates in |a| are kept.
+func Difference[T comparable](a, b []T) []T {
+	exclude := setOf(b)
+
+	return FilterSlice(a, func(elem T) bool {
+		_, ok := exclude[elem]
+		return !ok
+	})
+}
+
+// Intersection returns unique elements of |a| that are also in |b|, in the order of |a|.
+func Intersection[T comparable](a, b []T) []T {
+	include := setOf(b)
+
+	return Unique(FilterSlice(a, func(elem T) bool {
+		_, ok := include[elem]
+		return ok
+	}))
+}
+
+// Union returns unique elements of |a| followed by unique elements of |b| not in |a|.
+func Union[T comparable](a, b []T) []T {
+	if a == nil && b == nil {
+		return nil
+	}
+
+	all := make([]T, 0, len(a)+len(b))
+	all = append(all, a...)
+	all = append(all, b...)
+
+	return Unique(all)
+}
+
+// Flatten concatenates all slices in |arrs| into a new slice.
+func Flatten[T any](arrs [][]T) []T {
+	if arrs == nil {
+		return nil
+	}
+
+	var l int
+	for i := range arrs {
+		l += len(arrs[i])
+	}
+
+	flattened := make([]T, 0, l)
+	for i := range arrs {
+		flattened = append(flattened, arrs[i]...)
+	}
+
+	return flattened
+}
+
+// Zip pairs elements of |a| and |b| with the same indexes.
+// The result has the length of the shorter slice.
+func Zip[T any, U any](a []T, b []U) []Pair[T, U] {
+	if a == nil || b == nil {
+		return nil
+	}
+
+	l := min(len(a), len(b))
+	zipped := make([]Pair[T, U], l)
+	for i := 0; i < l; i++ {
+		zipped[i] = Pair[T, U]{First: a[i], Second: b[i]}
+	}
+
+	return zipped
+}
+
+// Unzip is the reverse of Zip.
+func Unzip[T any, U any](pairs []Pair[T, U]) ([]T, []U) {
+	if pairs == nil {
+		return nil, nil
+	}
+
+	a := make([]T, len(pairs))
+	b := make([]U, len(pairs))
+	for i := range pairs {
+		a[i], b[i] = pairs[i].First, pairs[i].Second
+	}
+
+	return a, b
+}
+
+// Associate maps |arr| to a map with keys and values from |f|.
+// If keys collide, the last element wins.
+func Associate[T any, K comparable, V any](arr []T, f func(elem T) (K, V)) map[K]V {
+	m := make(map[K]V, len(arr))
+	for _, elem := range arr {
+		k, v := f(elem)
+		m[k] = v
+	}
+
+	return m
+}
+
+// KeyBy maps |arr| to a map keyed by |key|. If keys collide, the last element wins.
+func KeyBy[T any, K comparable](arr []T, key func(elem T) K) map[K]T {
+	return Associate(arr, func(elem T) (K, T) {
+		return key(elem), elem
+	})
+}
+
+// CountBy counts elements of |arr| by their keys from |key|.
+func CountBy[T any, K comparable](arr []T, key func(elem T) K) map[K]int {
+	counts := make(map[K]int)
+	for _, elem := range arr {
+		counts[key(elem)]++
+	}
+
+	return counts
+}
+
+// ShuffleInPlace shuffles |arr| in-place with Fisher-Yates shuffle, using |rng|
+// for reproducible results (e.g. rand.New(rand.NewPCG(seed1, seed2))).
+// If |rng| is nil, the global random source is used.
+func ShuffleInPlace[T any](arr []T, rng *rand.Rand) {
+	swap := func(i, j int) {
+		arr[i], arr[j] = arr[j], arr[i]
+	}
+
+	if rng == nil {
+		rand.Shuffle(len(arr), swap)
+		return
+	}
+
+	rng.Shuffle(len(arr), swap)
+}
+
+// Shuffle returns a shuffled copy of |arr|. See ShuffleInPlace for |rng|.
+func Shuffle[T any](arr []T, rng *rand.Rand) []T {
+	if arr == nil {
+		return nil
+	}
+
+	shuffled := CopySlice(arr)
+	ShuffleInPlace(shuffled, rng)
+
+	return shuffled
+}
+
+// Sample returns |n| elements of |arr| chosen randomly without replacement,
+// or all elements in random order if |n| >= len(arr). See ShuffleInPlace for |rng|.
+func Sample[T any](arr []T, n int, rng *rand.Rand) []T {
+	if arr == nil || n <= 0 {
+		return nil
+	}
+
+	intN := rand.IntN
+	if rng != nil {
+		intN = rng.IntN
+	}
+
+	// Partial Fisher-Yates shuffle on a copy
+	pool := CopySlice(arr)
+	n = min(n, len(pool))
+
+	for i := 0; i < n; i++ {
+		j := i + intN(len(pool)-i)
+		pool[i], pool[j] = pool[j], pool[i]
+	}
+
+	return pool[:n:n]
+}
+
+func setOf[T comparable](arr []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(arr))
+	for _, elem := range arr {
+		set[elem] = struct{}{}
+	}
+
+	return set
+}
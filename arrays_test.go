@@ -2,7 +2,10 @@ package gsl
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -189,3 +192,307 @@ func testMap[T, U any](
 
 	return nil
 }
+
+func TestGroupByPartition(t *testing.T) {
+	words := []string{"apple", "bob", "avocado", "cat", "banana"}
+
+	groups := GroupBy(words, func(s string) byte { return s[0] })
+	expected := map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"bob", "banana"},
+		'c': {"cat"},
+	}
+
+	if !reflect.DeepEqual(expected, groups) {
+		t.Fatalf("unexpected groups -- expecting %v, got %v", expected, groups)
+	}
+
+	short, long := Partition(words, func(s string) bool { return len(s) <= 3 })
+	if !reflect.DeepEqual(short, []string{"bob", "cat"}) || !reflect.DeepEqual(long, []string{"apple", "avocado", "banana"}) {
+		t.Fatalf("unexpected partitions: %v %v", short, long)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	type test struct {
+		arr      []int
+		size     int
+		expected [][]int
+	}
+
+	tests := []test{
+		{arr: []int{1, 2, 3, 4, 5}, size: 2, expected: [][]int{{1, 2}, {3, 4}, {5}}},
+		{arr: []int{1, 2, 3, 4}, size: 2, expected: [][]int{{1, 2}, {3, 4}}},
+		{arr: []int{1, 2}, size: 5, expected: [][]int{{1, 2}}},
+		{arr: []int{}, size: 1, expected: [][]int{}},
+		{arr: nil, size: 1, expected: nil},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		actual := Chunk(test.arr, test.size)
+
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%d] unexpected chunks %v", i, actual)
+		}
+	}
+
+	// Appending to a chunk must not overwrite the next chunk
+	arr := []int{1, 2, 3, 4}
+	chunks := Chunk(arr, 2)
+	_ = append(chunks[0], 69)
+
+	if arr[2] != 3 {
+		t.Fatalf("append to chunk overwrote next chunk")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []int{1, 2, 2, 3, 4, 1}
+	b := []int{3, 4, 5, 5, 6}
+
+	type test struct {
+		name     string
+		actual   []int
+		expected []int
+	}
+
+	tests := []test{
+		{name: "unique", actual: Unique(a), expected: []int{1, 2, 3, 4}},
+		{name: "difference", actual: Difference(a, b), expected: []int{1, 2, 2, 1}},
+		{name: "intersection", actual: Intersection(a, b), expected: []int{3, 4}},
+		{name: "union", actual: Union(a, b), expected: []int{1, 2, 3, 4, 5, 6}},
+		{name: "unique by", actual: UniqueBy(a, func(i int) int { return i % 2 }), expected: []int{1, 2}},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if !reflect.DeepEqual(test.expected, test.actual) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%s] unexpected result %v", test.name, test.actual)
+		}
+	}
+}
+
+func TestFlattenZip(t *testing.T) {
+	flattened := Flatten([][]int{{1}, nil, {2, 3}, {}})
+	if !reflect.DeepEqual(flattened, []int{1, 2, 3}) {
+		t.Fatalf("unexpected flattened slice %v", flattened)
+	}
+
+	zipped := Zip([]int{1, 2, 3}, []string{"one", "two"})
+	expected := []Pair[int, string]{{1, "one"}, {2, "two"}}
+	if !reflect.DeepEqual(expected, zipped) {
+		t.Fatalf("unexpected zipped slice %v", zipped)
+	}
+
+	nums, strs := Unzip(zipped)
+	if !reflect.DeepEqual(nums, []int{1, 2}) || !reflect.DeepEqual(strs, []string{"one", "two"}) {
+		t.Fatalf("unexpected unzipped slices %v %v", nums, strs)
+	}
+}
+
+func TestAssociate(t *testing.T) {
+	words := []string{"a", "bb", "cc", "ddd"}
+
+	upper := Associate(words, func(s string) (string, string) { return s, strings.ToUpper(s) })
+	if !reflect.DeepEqual(upper, map[string]string{"a": "A", "bb": "BB", "cc": "CC", "ddd": "DDD"}) {
+		t.Fatalf("unexpected associated map %v", upper)
+	}
+
+	byLen := KeyBy(words, func(s string) int { return len(s) })
+	if !reflect.DeepEqual(byLen, map[int]string{1: "a", 2: "cc", 3: "ddd"}) {
+		t.Fatalf("unexpected keyed map %v", byLen)
+	}
+
+	counts := CountBy(words, func(s string) int { return len(s) })
+	if !reflect.DeepEqual(counts, map[int]int{1: 1, 2: 2, 3: 1}) {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+}
+
+func TestShuffleSample(t *testing.T) {
+	arr := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	shuffled0 := Shuffle(arr, rand.New(rand.NewPCG(1, 2)))
+	shuffled1 := Shuffle(arr, rand.New(rand.NewPCG(1, 2)))
+
+	if !reflect.DeepEqual(shuffled0, shuffled1) {
+		t.Fatalf("same seeds produced different shuffles: %v %v", shuffled0, shuffled1)
+	}
+
+	sorted := CopySlice(shuffled0)
+	sort.Ints(sorted)
+	if !reflect.DeepEqual(arr, sorted) {
+		t.Fatalf("shuffle changed elements: %v", shuffled0)
+	}
+
+	if arr[0] != 1 || arr[9] != 10 {
+		t.Fatalf("Shuffle modified its input")
+	}
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	for n := 0; n <= 12; n++ {
+		sample := Sample(arr, n, rng)
+		if l := len(sample); l != min(n, len(arr)) {
+			t.Fatalf("unexpected sample length %d for n = %d", l, n)
+		}
+
+		if unique := Unique(sample); len(unique) != len(sample) {
+			t.Fatalf("sample has duplicates: %v", sample)
+		}
+
+		if diff := Difference(sample, arr); len(diff) != 0 {
+			t.Fatalf("sample has unknown elements: %v", diff)
+		}
+	}
+}
+
+func benchSlice(n int) []int {
+	rng := rand.New(rand.NewPCG(1, 1))
+	arr := make([]int, n)
+	for i := range arr {
+		arr[i] = rng.IntN(n / 2)
+	}
+
+	return arr
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		GroupBy(arr, func(x int) int { return x % 100 })
+	}
+}
+
+func BenchmarkUnique(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Unique(arr)
+	}
+}
+
+func BenchmarkIntersection(b *testing.B) {
+	arr0, arr1 := benchSlice(10000), benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Intersection(arr0, arr1)
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Chunk(arr, 64)
+	}
+}
+
+func BenchmarkShuffle(b *testing.B) {
+	arr := benchSlice(10000)
+	rng := rand.New(rand.NewPCG(1, 2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Shuffle(arr, rng)
+	}
+}
+
+func BenchmarkSample(b *testing.B) {
+	arr := benchSlice(10000)
+	rng := rand.New(rand.NewPCG(1, 2))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Sample(arr, 100, rng)
+	}
+}
+
+func BenchmarkPartition(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Partition(arr, func(x int) bool { return x%2 == 0 })
+	}
+}
+
+func BenchmarkDifference(b *testing.B) {
+	arr0, arr1 := benchSlice(10000), benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Difference(arr0, arr1)
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	arr0, arr1 := benchSlice(10000), benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Union(arr0, arr1)
+	}
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	arrs := Chunk(benchSlice(10000), 64)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Flatten(arrs)
+	}
+}
+
+func BenchmarkZip(b *testing.B) {
+	arr0, arr1 := benchSlice(10000), benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Zip(arr0, arr1)
+	}
+}
+
+func BenchmarkUnzip(b *testing.B) {
+	pairs := Zip(benchSlice(10000), benchSlice(10000))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Unzip(pairs)
+	}
+}
+
+func BenchmarkAssociate(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Associate(arr, func(x int) (int, int) { return x, x * 2 })
+	}
+}
+
+func BenchmarkKeyBy(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		KeyBy(arr, func(x int) int { return x })
+	}
+}
+
+func BenchmarkCountBy(b *testing.B) {
+	arr := benchSlice(10000)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		CountBy(arr, func(x int) int { return x % 100 })
+	}
+}
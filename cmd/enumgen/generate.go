@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/soyart/gsl"
+)
+
+type options struct {
+	trimPrefix string
+	nameCase   string
+}
+
+var caseFuncs = map[string]func(string) string{
+	"":       func(s string) string { return s },
+	"snake":  gsl.ToSnakeCase[string],
+	"kebab":  gsl.ToKebabCase[string],
+	"camel":  gsl.ToCamelCase[string],
+	"pascal": gsl.ToPascalCase[string],
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+}
+
+type value struct {
+	Const string
+	Name  string
+}
+
+type enumType struct {
+	Type     string
+	IsString bool
+	Values   []value
+}
+
+// Var is the name of the enum registry variable
+func (e enumType) Var() string {
+	return "enum" + capitalize(e.Type)
+}
+
+// Prefix prefixes exported names of functions for exported types
+func (e enumType) Prefix(name string) string {
+	if ast.IsExported(e.Type) {
+		return name + e.Type
+	}
+
+	return strings.ToLower(name) + capitalize(e.Type)
+}
+
+// Suffix suffixes type names to function names
+func (e enumType) Suffix(name string) string {
+	return e.Type + name
+}
+
+type pkg struct {
+	name  string
+	types *types.Package
+}
+
+// generate loads the package containing |filename|,
+// and returns formatted source code for |typeNames|.
+func generate(filename string, typeNames []string, opts options) ([]byte, error) {
+	toCase, ok := caseFuncs[opts.nameCase]
+	if !ok {
+		return nil, fmt.Errorf("unknown case %s", opts.nameCase)
+	}
+
+	p, err := loadPackage(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	enums := make([]enumType, len(typeNames))
+	for i, typeName := range typeNames {
+		typeName = strings.TrimSpace(typeName)
+
+		e, err := p.enum(typeName, opts.trimPrefix, toCase)
+		if err != nil {
+			return nil, err
+		}
+
+		enums[i] = e
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Package string
+		Enums   []enumType
+	}{
+		Package: p.name,
+		Enums:   enums,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// loadPackage parses and type-checks all non-test Go files in the directory of |filename|,
+// so that constant values can be evaluated.
+//
+// Type errors are ignored, because the package may not compile before generating,
+// e.g. if it already uses generated methods. Constants whose values cannot be
+// evaluated are reported by (*pkg).enum.
+func loadPackage(filename string) (*pkg, error) {
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package directory %s: %w", dir, err)
+	}
+
+	p := new(pkg)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		if filepath.Base(filename) == name {
+			p.name = f.Name.Name
+		}
+
+		files = append(files, f)
+	}
+
+	if p.name == "" {
+		return nil, fmt.Errorf("file %s not found", filename)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+
+	p.types, _ = conf.Check(p.name, fset, files, nil)
+
+	return p, nil
+}
+
+// consts returns constants of type |named| in declaration order
+func (p *pkg) consts(named types.Type) []*types.Const {
+	var consts []*types.Const
+
+	scope := p.types.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	return consts
+}
+
+func (p *pkg) enum(typeName, trimPrefix string, toCase func(string) string) (enumType, error) {
+	obj, ok := p.types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return enumType{}, fmt.Errorf("type %s not found", typeName)
+	}
+
+	e := enumType{Type: typeName}
+
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	switch {
+	case ok && basic.Info()&types.IsString != 0:
+		e.IsString = true
+	case ok && basic.Info()&types.IsInteger != 0:
+	default:
+		return enumType{}, fmt.Errorf("type %s is not a string or integer type", typeName)
+	}
+
+	consts := p.consts(obj.Type())
+	if len(consts) == 0 {
+		return enumType{}, fmt.Errorf("type %s has no constants", typeName)
+	}
+
+	// Constants with the same value as an earlier constant are aliases,
+	// which are skipped because each value has only 1 name
+	values := make(map[string]bool)
+	seen := make(map[string]string)
+
+	for _, c := range consts {
+		if c.Val().Kind() == constant.Unknown {
+			return enumType{}, fmt.Errorf("type %s: cannot evaluate constant %s", typeName, c.Name())
+		}
+
+		key := c.Val().ExactString()
+		if values[key] {
+			continue
+		}
+
+		values[key] = true
+
+		v := value{Const: c.Name()}
+
+		if !e.IsString {
+			v.Name = toCase(strings.TrimPrefix(c.Name(), trimPrefix))
+			if v.Name == "" {
+				return enumType{}, fmt.Errorf("type %s: constant %s has empty name", typeName, c.Name())
+			}
+
+			if other, dup := seen[v.Name]; dup {
+				return enumType{}, fmt.Errorf("type %s: constants %s and %s have the same name %s", typeName, other, c.Name(), v.Name)
+			}
+
+			seen[v.Name] = c.Name()
+		}
+
+		e.Values = append(e.Values, v)
+	}
+
+	return e, nil
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+var tmpl = template.Must(template.New("enumgen").Parse(`// Code generated by enumgen; DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/soyart/gsl/enum"
+)
+
+{{ range .Enums -}}
+{{ $type := .Type -}}
+{{ $var := .Var -}}
+{{ if .IsString -}}
+var {{ $var }} = enum.NewString(
+{{- range .Values }}
+	{{ .Const }},
+{{- end }}
+)
+{{- else -}}
+var {{ $var }} = enum.New(map[{{ $type }}]string{
+{{- range .Values }}
+	{{ .Const }}: {{ printf "%q" .Name }},
+{{- end }}
+})
+{{- end }}
+
+// {{ .Suffix "Values" }} returns all valid values of {{ $type }}.
+func {{ .Suffix "Values" }}() []{{ $type }} {
+	return {{ $var }}.Values()
+}
+
+// {{ .Prefix "Parse" }} returns the {{ $type }} named |s|, or enum.ErrUnknown if there is none.
+func {{ .Prefix "Parse" }}(s string) ({{ $type }}, error) {
+	return {{ $var }}.Parse(s)
+}
+
+func (x {{ $type }}) String() string {
+	return {{ $var }}.String(x)
+}
+
+func (x {{ $type }}) IsValid() bool {
+	return {{ $var }}.IsValid(x)
+}
+
+func (x {{ $type }}) MarshalText() ([]byte, error) {
+	return {{ $var }}.MarshalText(x)
+}
+
+func (x *{{ $type }}) UnmarshalText(b []byte) error {
+	return {{ $var }}.UnmarshalText(x, b)
+}
+
+func (x {{ $type }}) MarshalYAML() (interface{}, error) {
+	return {{ $var }}.MarshalYAML(x)
+}
+
+func (x *{{ $type }}) UnmarshalYAML(node *yaml.Node) error {
+	return {{ $var }}.UnmarshalYAML(x, node)
+}
+
+{{ end -}}
+`))
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	type test struct {
+		file   string
+		types  []string
+		opts   options
+		golden string
+	}
+
+	tests := []test{
+		{
+			file:   "testdata/enums/enums.go",
+			types:  []string{"Color"},
+			opts:   options{trimPrefix: "Color", nameCase: "snake"},
+			golden: "testdata/colors.golden",
+		},
+		{
+			file:   "testdata/enums/enums.go",
+			types:  []string{"Status", "weekday"},
+			golden: "testdata/enums.golden",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual, err := generate(test.file, test.types, test.opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+
+		if *update {
+			if err := os.WriteFile(test.golden, actual, 0o644); err != nil {
+				t.Fatalf("failed to update golden file: %s", err.Error())
+			}
+		}
+
+		expected, err := os.ReadFile(test.golden)
+		if err != nil {
+			t.Fatalf("failed to read golden file: %s", err.Error())
+		}
+
+		if !bytes.Equal(expected, actual) {
+			t.Logf("Expecting:\n%s", expected)
+			t.Logf("Actual:\n%s", actual)
+
+			t.Fatalf("output does not match golden file %s", test.golden)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	file := filepath.Join("testdata", "enums", "enums.go")
+
+	type test struct {
+		types []string
+		opts  options
+	}
+
+	tests := []test{
+		{types: []string{"Missing"}},
+		{types: []string{"Color"}, opts: options{nameCase: "screaming"}},
+		{types: []string{"Color"}, opts: options{nameCase: "lower", trimPrefix: "ColorRed"}},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if _, err := generate(file, test.types, test.opts); err == nil {
+			t.Fatalf("[%d] expecting error for types %v", i, test.types)
+		}
+	}
+
+	bad := filepath.Join("testdata", "bad", "bad.go")
+	for _, typeName := range []string{"Level", "Point"} {
+		if _, err := generate(bad, []string{typeName}, options{}); err == nil {
+			t.Fatalf("expecting error for type %s", typeName)
+		}
+	}
+
+	if _, err := generate(filepath.Join("testdata", "missing.go"), []string{"Color"}, options{}); err == nil {
+		t.Fatal("expecting error for missing file")
+	}
+}
@@ -0,0 +1,62 @@
+// enumgen generates enum methods for string-backed and integer-backed
+// types and their constants, using package github.com/soyart/gsl/enum.
+//
+// It is meant to be used with go generate:
+//
+//	//go:generate go run github.com/soyart/gsl/cmd/enumgen -type=Color,Status
+//
+// For each type, enumgen collects constants of the type declared in the package,
+// and emits a package-level enum registry, <Type>Values, Parse<Type>, and
+// String, IsValid, MarshalText, UnmarshalText, MarshalYAML and UnmarshalYAML methods,
+// so that unknown values are rejected when marshalling and unmarshalling.
+//
+// Constants of integer-backed types are named by their identifiers,
+// optionally with -trimprefix removed and converted with -case,
+// e.g. ColorDarkRed is named "dark_red" with -trimprefix=Color -case=snake.
+// Constants of string-backed types are named by their values.
+// Constants with the same value as an earlier constant, e.g. aliases, are skipped.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of enum type names (required)")
+	input := flag.String("file", os.Getenv("GOFILE"), "input Go file, defaults to $GOFILE")
+	output := flag.String("output", "", "output file name, defaults to <file>_enum.go")
+	trimPrefix := flag.String("trimprefix", "", "prefix to remove from constant names of integer-backed types")
+	nameCase := flag.String("case", "", "case of names of integer-backed types: snake, kebab, camel, pascal, lower or upper")
+
+	flag.Parse()
+
+	if *typeNames == "" || *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *output == "" {
+		base := strings.TrimSuffix(*input, filepath.Ext(*input))
+		*output = base + "_enum.go"
+	}
+
+	opts := options{
+		trimPrefix: *trimPrefix,
+		nameCase:   *nameCase,
+	}
+
+	src, err := generate(*input, strings.Split(*typeNames, ","), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "enumgen: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil { //nolint:gosec
+		fmt.Fprintf(os.Stderr, "enumgen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
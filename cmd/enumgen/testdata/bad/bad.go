@@ -0,0 +1,12 @@
+package bad
+
+type Level int
+
+const (
+	LevelLow  Level = 1
+	LevelHigh Level = Level(missing)
+)
+
+type Point struct {
+	X, Y int
+}
@@ -0,0 +1,35 @@
+package enums
+
+import "math"
+
+type Color uint8
+
+const (
+	_ Color = iota
+	ColorRed
+	ColorDarkRed
+	ColorHTTPBlue
+
+	// Aliases are skipped
+	ColorCrimson = ColorRed
+)
+
+const ColorWhite Color = math.MaxUint8
+
+type Status string
+
+const (
+	StatusActive   Status = "active"
+	StatusInactive Status = "inactive"
+	StatusEnabled         = StatusActive
+
+	defaultTimeout = 5
+)
+
+type weekday int
+
+const (
+	sunday = weekday(iota)
+	monday
+	tuesday
+)
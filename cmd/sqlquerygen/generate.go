@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/soyart/gsl/sqlquery"
+)
+
+const directiveTable = "sqlquery:table"
+
+type field struct {
+	sqlquery.Tag
+
+	// Path is the selector path from the receiver, e.g. "Audit.CreatedBy"
+	Path string
+}
+
+type model struct {
+	Type   string
+	Table  string
+	Fields []field
+}
+
+func (m model) Create() []field {
+	var fields []field
+	for _, f := range m.Fields {
+		if !f.ReadOnly {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func (m model) Update() []field {
+	var fields []field
+	for _, f := range m.Fields {
+		if !f.ReadOnly && !f.PrimaryKey {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+func (m model) PrimaryKeys() []field {
+	var fields []field
+	for _, f := range m.Fields {
+		if f.PrimaryKey {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+type pkg struct {
+	name    string
+	structs map[string]*ast.StructType
+	docs    map[string]*ast.CommentGroup
+}
+
+// generate parses the package containing |filename|,
+// and returns formatted source code for |typeNames|.
+func generate(filename string, typeNames []string) ([]byte, error) {
+	p, err := parsePackage(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]model, len(typeNames))
+	for i, typeName := range typeNames {
+		typeName = strings.TrimSpace(typeName)
+
+		m, err := p.model(typeName)
+		if err != nil {
+			return nil, err
+		}
+
+		models[i] = m
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, struct {
+		Package string
+		Models  []model
+	}{
+		Package: p.name,
+		Models:  models,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// parsePackage parses all non-test Go files in the directory of |filename|
+func parsePackage(filename string) (*pkg, error) {
+	dir := filepath.Dir(filename)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read package directory %s: %w", dir, err)
+	}
+
+	p := &pkg{
+		structs: make(map[string]*ast.StructType),
+		docs:    make(map[string]*ast.CommentGroup),
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		if filepath.Base(filename) == name {
+			p.name = f.Name.Name
+		}
+
+		p.collect(f)
+	}
+
+	if p.name == "" {
+		return nil, fmt.Errorf("file %s not found", filename)
+	}
+
+	return p, nil
+}
+
+func (p *pkg) collect(f *ast.File) {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+
+			p.structs[typeSpec.Name.Name] = structType
+			p.docs[typeSpec.Name.Name] = doc
+		}
+	}
+}
+
+func (p *pkg) model(typeName string) (model, error) {
+	structType, ok := p.structs[typeName]
+	if !ok {
+		return model{}, fmt.Errorf("struct type %s not found", typeName)
+	}
+
+	m := model{
+		Type:  typeName,
+		Table: typeName,
+	}
+
+	if doc := p.docs[typeName]; doc != nil {
+		for _, c := range doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+			if table, ok := strings.CutPrefix(text, directiveTable); ok {
+				m.Table = strings.TrimSpace(table)
+			}
+		}
+	}
+
+	fields, err := p.fields(structType, "")
+	if err != nil {
+		return model{}, fmt.Errorf("type %s: %w", typeName, err)
+	}
+
+	if len(fields) == 0 {
+		return model{}, fmt.Errorf("type %s: %w", typeName, sqlquery.ErrNoColumnTags)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		if seen[f.Column] {
+			return model{}, fmt.Errorf("type %s: duplicate column %s", typeName, f.Column)
+		}
+
+		seen[f.Column] = true
+	}
+
+	m.Fields = fields
+	return m, nil
+}
+
+// fields collects tagged fields of |structType|, flattening
+// untagged embedded structs declared in the same package.
+// Like sqlquery.StructModel, tagged embedded fields are mapped as single columns.
+func (p *pkg) fields(structType *ast.StructType, prefix string) ([]field, error) {
+	var fields []field
+
+	for _, f := range structType.Fields.List {
+		tagValue, hasTag := lookupTag(f.Tag)
+
+		if len(f.Names) == 0 && hasTag {
+			tag, ok := sqlquery.ParseTag(tagValue)
+			name := embeddedName(f.Type)
+
+			if ok && ast.IsExported(name) {
+				fields = append(fields, field{Tag: tag, Path: prefix + name})
+			}
+
+			continue
+		}
+
+		if len(f.Names) == 0 {
+			ident, ok := f.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			embedded, ok := p.structs[ident.Name]
+			if !ok {
+				return nil, fmt.Errorf("embedded type %s is not a struct in this package", ident.Name)
+			}
+
+			nested, err := p.fields(embedded, prefix+ident.Name+".")
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, nested...)
+			continue
+		}
+
+		tag, ok := sqlquery.ParseTag(tagValue)
+		if !ok {
+			continue
+		}
+
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+
+			fields = append(fields, field{Tag: tag, Path: prefix + name.Name})
+		}
+	}
+
+	return fields, nil
+}
+
+// embeddedName returns the field name of embedded type |expr|,
+// e.g. T for T, *T, pkg.T and *pkg.T
+func embeddedName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+
+	return ""
+}
+
+func lookupTag(lit *ast.BasicLit) (string, bool) {
+	if lit == nil {
+		return "", false
+	}
+
+	unquoted, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+
+	return reflect.StructTag(unquoted).Lookup(sqlquery.TagKey)
+}
+
+var tmpl = template.Must(template.New("sqlquerygen").Parse(`// Code generated by sqlquerygen; DO NOT EDIT.
+
+package {{ .Package }}
+
+import "github.com/soyart/gsl/sqlquery"
+
+{{ range .Models -}}
+{{ $type := .Type -}}
+func (x *{{ $type }}) TableName() string {
+	return {{ printf "%q" .Table }}
+}
+
+func (x *{{ $type }}) sqlqueryCreate() ([]string, []interface{}) {
+	columns := make([]string, 0, {{ len .Create }})
+	values := make([]interface{}, 0, {{ len .Create }})
+{{ range .Create }}
+{{- if .OmitEmpty }}
+	if !sqlquery.IsZero(x.{{ .Path }}) {
+		columns, values = append(columns, {{ printf "%q" .Column }}), append(values, x.{{ .Path }})
+	}
+{{- else }}
+	columns, values = append(columns, {{ printf "%q" .Column }}), append(values, x.{{ .Path }})
+{{- end }}
+{{- end }}
+
+	return columns, values
+}
+
+func (x *{{ $type }}) sqlqueryUpdate() ([]string, []interface{}) {
+	columns := make([]string, 0, {{ len .Update }})
+	values := make([]interface{}, 0, {{ len .Update }})
+{{ range .Update }}
+{{- if .OmitEmpty }}
+	if !sqlquery.IsZero(x.{{ .Path }}) {
+		columns, values = append(columns, {{ printf "%q" .Column }}), append(values, x.{{ .Path }})
+	}
+{{- else }}
+	columns, values = append(columns, {{ printf "%q" .Column }}), append(values, x.{{ .Path }})
+{{- end }}
+{{- end }}
+
+	return columns, values
+}
+
+func (x *{{ $type }}) ColumnsCreate() []string {
+	columns, _ := x.sqlqueryCreate()
+	return columns
+}
+
+func (x *{{ $type }}) ValuesCreate() []interface{} {
+	_, values := x.sqlqueryCreate()
+	return values
+}
+
+func (x *{{ $type }}) MapColumnValuesCreate() map[string]interface{} {
+	return sqlquery.MapColumnValues(x.sqlqueryCreate())
+}
+
+func (x *{{ $type }}) ColumnsUpdate() []string {
+	columns, _ := x.sqlqueryUpdate()
+	return columns
+}
+
+func (x *{{ $type }}) ValuesUpdate() []interface{} {
+	_, values := x.sqlqueryUpdate()
+	return values
+}
+
+func (x *{{ $type }}) MapColumnValuesUpdate() map[string]interface{} {
+	return sqlquery.MapColumnValues(x.sqlqueryUpdate())
+}
+{{ if .PrimaryKeys }}
+func (x *{{ $type }}) Where() map[string]interface{} {
+	return map[string]interface{}{
+{{- range .PrimaryKeys }}
+		{{ printf "%q" .Column }}: x.{{ .Path }},
+{{- end }}
+	}
+}
+{{ end }}
+{{ end -}}
+`))
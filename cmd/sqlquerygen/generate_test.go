@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	type test struct {
+		file   string
+		types  []string
+		golden string
+	}
+
+	tests := []test{
+		{
+			file:   "testdata/models/models.go",
+			types:  []string{"User", "Post", "Comment"},
+			golden: "testdata/models.golden",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual, err := generate(test.file, test.types)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err.Error())
+		}
+
+		if *update {
+			if err := os.WriteFile(test.golden, actual, 0o644); err != nil {
+				t.Fatalf("failed to update golden file: %s", err.Error())
+			}
+		}
+
+		expected, err := os.ReadFile(test.golden)
+		if err != nil {
+			t.Fatalf("failed to read golden file: %s", err.Error())
+		}
+
+		if !bytes.Equal(expected, actual) {
+			t.Logf("Expecting:\n%s", expected)
+			t.Logf("Actual:\n%s", actual)
+
+			t.Fatalf("output does not match golden file %s", test.golden)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	file := filepath.Join("testdata", "models", "models.go")
+
+	if _, err := generate(file, []string{"Missing"}); err == nil {
+		t.Fatal("expecting error for missing type")
+	}
+
+	if _, err := generate(file, []string{"NoTags"}); err == nil {
+		t.Fatal("expecting error for type without db tags")
+	}
+
+	if _, err := generate(filepath.Join("testdata", "missing.go"), []string{"User"}); err == nil {
+		t.Fatal("expecting error for missing file")
+	}
+}
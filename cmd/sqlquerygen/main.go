@@ -0,0 +1,54 @@
+// sqlquerygen generates sqlquery model methods for structs with `db` tags.
+//
+// It is meant to be used with go generate:
+//
+//	//go:generate go run github.com/soyart/gsl/cmd/sqlquerygen -type=User,Post
+//
+// For each type, sqlquerygen emits TableName, ColumnsCreate, ValuesCreate,
+// MapColumnValuesCreate, ColumnsUpdate, ValuesUpdate, MapColumnValuesUpdate
+// and (if the type has `pk` columns) Where, honoring the same tag options as
+// sqlquery.StructModel.
+//
+// Table names default to the type name, and can be overridden
+// with a `sqlquery:table NAME` directive in the type's doc comment:
+//
+//	//sqlquery:table USERS
+//	type User struct { ... }
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names (required)")
+	input := flag.String("file", os.Getenv("GOFILE"), "input Go file, defaults to $GOFILE")
+	output := flag.String("output", "", "output file name, defaults to <file>_sqlquery.go")
+
+	flag.Parse()
+
+	if *typeNames == "" || *input == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *output == "" {
+		base := strings.TrimSuffix(*input, filepath.Ext(*input))
+		*output = base + "_sqlquery.go"
+	}
+
+	src, err := generate(*input, strings.Split(*typeNames, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sqlquerygen: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil { //nolint:gosec
+		fmt.Fprintf(os.Stderr, "sqlquerygen: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
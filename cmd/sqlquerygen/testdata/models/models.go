@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+type Audit struct {
+	CreatedBy string    `db:"CREATED_BY"`
+	CreatedAt time.Time `db:"CREATED_AT,readonly"`
+}
+
+// User is a test model with table directive.
+//
+//sqlquery:table USERS
+type User struct {
+	Audit
+
+	ID       uint64 `db:"ID,pk,readonly"`
+	Name     string `db:"NAME"`
+	Nickname string `db:"NICKNAME,omitempty"`
+	Ignored  string `db:"-"`
+	NoTag    string
+	internal string `db:"INTERNAL"`
+}
+
+type Post struct {
+	UserID uint64 `db:"USER_ID,pk"`
+	Slug   string `db:"SLUG,pk"`
+	Body   string `db:"BODY"`
+}
+
+type Label string
+
+// Comment has tagged embedded fields, which are mapped as single columns.
+type Comment struct {
+	Audit `db:"AUDIT"`
+	Label `db:"LABEL,omitempty"`
+
+	*time.Location `db:"LOCATION"`
+
+	ID uint64 `db:"ID,pk"`
+}
+
+type NoTags struct {
+	A int
+}
@@ -0,0 +1,52 @@
+package concurrent
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/soyart/gsl"
+)
+
+// MapResults maps |items| with |f| concurrently, using at most |limit| goroutines
+// (or one goroutine per item if |limit| <= 0). The results are in the same order as |items|,
+// and a panic in |f| is recovered as the item's error, so one bad item does not fail the batch.
+func MapResults[T any, U any](items []T, limit int, f func(T) (U, error)) []gsl.Result[U] {
+	results := make([]gsl.Result[U], len(items))
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i] = protectResult(items[i], f)
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func protectResult[T any, U any](item T, f func(T) (U, error)) (result gsl.Result[U]) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = gsl.Err[U](fmt.Errorf("recovered panic: %v", r))
+		}
+	}()
+
+	return gsl.ResultOf(f(item))
+}
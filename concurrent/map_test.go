@@ -0,0 +1,139 @@
+package concurrent
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMapResults(t *testing.T) {
+	errOdd := errors.New("odd")
+
+	items := make([]int, 50)
+	for i := range items {
+		items[i] = i
+	}
+
+	type test struct {
+		limit       int
+		maxParallel int64
+	}
+
+	tests := []test{
+		{limit: 1, maxParallel: 1},
+		{limit: 3, maxParallel: 3},
+		{limit: 100, maxParallel: int64(len(items))},
+		{limit: 0, maxParallel: int64(len(items))},
+		{limit: -1, maxParallel: int64(len(items))},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		var running, maxRunning int64
+		results := MapResults(items, test.limit, func(item int) (int, error) {
+			n := atomic.AddInt64(&running, 1)
+			defer atomic.AddInt64(&running, -1)
+
+			for {
+				max := atomic.LoadInt64(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt64(&maxRunning, max, n) {
+					break
+				}
+			}
+
+			// Later items finish first, so results are not in completion order
+			time.Sleep(time.Duration(len(items)-item) * 10 * time.Microsecond)
+
+			switch {
+			case item == 13:
+				panic("bad item")
+			case item%2 != 0:
+				return 0, errOdd
+			}
+
+			return item * 2, nil
+		})
+
+		if len(results) != len(items) {
+			t.Fatalf("[%d] unexpected results length %d", i, len(results))
+		}
+
+		if max := atomic.LoadInt64(&maxRunning); max > test.maxParallel {
+			t.Fatalf("[%d] limit %d exceeded: %d goroutines", i, test.limit, max)
+		}
+
+		for j, result := range results {
+			value, err := result.Get()
+
+			switch {
+			case j == 13:
+				if err == nil || !strings.Contains(err.Error(), "bad item") {
+					t.Fatalf("[%d] expecting recovered panic for item %d, got %v", i, j, err)
+				}
+
+			case j%2 != 0:
+				if !errors.Is(err, errOdd) {
+					t.Fatalf("[%d] expecting errOdd for item %d, got %v", i, j, err)
+				}
+
+			default:
+				if err != nil {
+					t.Fatalf("[%d] unexpected error for item %d: %s", i, j, err.Error())
+				}
+
+				if value != j*2 {
+					t.Fatalf("[%d] unexpected value for item %d: %d", i, j, value)
+				}
+			}
+		}
+	}
+}
+
+func TestMapResultsNoLimit(t *testing.T) {
+	items := make([]int, 20)
+
+	for _, limit := range []int{0, -1} {
+		// Every call blocks until all items are running,
+		// which only succeeds with one goroutine per item
+		var started sync.WaitGroup
+		started.Add(len(items))
+
+		all := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(all)
+		}()
+
+		results := MapResults(items, limit, func(int) (struct{}, error) {
+			started.Done()
+
+			select {
+			case <-all:
+				return struct{}{}, nil
+			case <-time.After(5 * time.Second):
+				return struct{}{}, errors.New("not all items are running concurrently")
+			}
+		})
+
+		for i, result := range results {
+			if err := result.Err(); err != nil {
+				t.Fatalf("limit %d: item %d: %s", limit, i, err.Error())
+			}
+		}
+	}
+}
+
+func TestMapResultsEmpty(t *testing.T) {
+	results := MapResults(nil, 3, func(int) (int, error) {
+		t.Fatal("unexpected call")
+		return 0, nil
+	})
+
+	if len(results) != 0 {
+		t.Fatalf("unexpected results length %d", len(results))
+	}
+}
@@ -0,0 +1,500 @@
+package gsl
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	typeDuration        = reflect.TypeFor[time.Duration]()
+	typeTime            = reflect.TypeFor[time.Time]()
+	typeTextMarshaler   = reflect.TypeFor[encoding.TextMarshaler]()
+	typeTextUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+type converterKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+var (
+	convertersMut sync.RWMutex
+	converters    = make(map[converterKey]func(reflect.Value) (reflect.Value, error))
+)
+
+// RegisterConverter registers |f| as the converter from S to T for ConvertValue and InterfaceTo,
+// replacing any converter previously registered for the same types.
+// Registered converters take precedence over built-in conversions,
+// and are also used for elements of slices and maps. It is safe for concurrent use.
+func RegisterConverter[S any, T any](f func(S) (T, error)) {
+	key := converterKey{from: reflect.TypeFor[S](), to: reflect.TypeFor[T]()}
+
+	convertersMut.Lock()
+	defer convertersMut.Unlock()
+
+	converters[key] = func(v reflect.Value) (reflect.Value, error) {
+		t, err := f(v.Interface().(S))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		return reflect.ValueOf(&t).Elem(), nil
+	}
+}
+
+func converterFor(from, to reflect.Type) func(reflect.Value) (reflect.Value, error) {
+	convertersMut.RLock()
+	defer convertersMut.RUnlock()
+
+	return converters[converterKey{from: from, to: to}]
+}
+
+// ConvertValue converts |v| to a value of type |target|. The conversion rules are:
+//
+//   - nil (including nil pointers, slices and maps) converts to the zero value of nilable types
+//     (pointers, slices, maps, interfaces, channels and functions), and fails for other types
+//   - converters registered with RegisterConverter are used first
+//   - values assignable to |target| are returned as is
+//   - pointers are dereferenced, and pointer targets are allocated
+//   - numbers convert to other numeric types only if lossless, e.g. 3.0 to int,
+//     but not 3.5 to int or 300 to uint8
+//   - strings are parsed into numbers, bools, time.Duration (e.g. "1m30s"), time.Time (RFC 3339),
+//     and encoding.TextUnmarshaler types
+//   - numbers, bools, time.Duration, time.Time and encoding.TextMarshaler types are formatted as strings,
+//     e.g. 69 converts to "69", not "E"
+//   - slices and arrays are converted element-wise, and maps key-wise and value-wise
+//   - other values are converted with reflect only between types of the same kind,
+//     e.g. named string types, and between strings and []byte or []rune
+//
+// All errors wrap ErrNotConvertible, and errors from elements report their index or key.
+func ConvertValue(v interface{}, target reflect.Type) (reflect.Value, error) {
+	if target == nil {
+		return reflect.Value{}, errors.Wrap(ErrNotConvertible, "nil target type")
+	}
+
+	out, err := convertValue(reflect.ValueOf(v), target)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return out, nil
+}
+
+func isNilable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+		return true
+	}
+
+	return false
+}
+
+func isNil(v reflect.Value) bool {
+	return !v.IsValid() || (isNilable(v.Kind()) && v.IsNil())
+}
+
+func notConvertible(v reflect.Value, target reflect.Type, reason string) error {
+	from := "nil"
+	if v.IsValid() {
+		from = v.Type().String()
+	}
+
+	if reason == "" {
+		return errors.Wrapf(ErrNotConvertible, "cannot convert type %s to type %s", from, target.String())
+	}
+
+	return errors.Wrapf(ErrNotConvertible, "cannot convert type %s to type %s: %s", from, target.String(), reason)
+}
+
+func convertValue(v reflect.Value, target reflect.Type) (reflect.Value, error) {
+	// Unwrap interfaces, e.g. elements of []interface{}
+	for v.IsValid() && v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if isNil(v) {
+		if isNilable(target.Kind()) {
+			return reflect.Zero(target), nil
+		}
+
+		// Nil []byte or map is still a value, e.g. nil []byte to string
+		if !v.IsValid() || v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			return reflect.Value{}, notConvertible(v, target, "nil value")
+		}
+	}
+
+	if f := converterFor(v.Type(), target); f != nil {
+		out, err := f(v)
+		if err != nil {
+			return reflect.Value{}, errors.Wrapf(ErrNotConvertible, "converter from %s to %s: %s", v.Type(), target, err.Error())
+		}
+
+		return out, nil
+	}
+
+	if v.Type().AssignableTo(target) {
+		out := reflect.New(target).Elem()
+		out.Set(v)
+
+		return out, nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		return convertValue(v.Elem(), target)
+	}
+
+	if target.Kind() == reflect.Pointer {
+		elem, err := convertValue(v, target.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out := reflect.New(target.Elem())
+		out.Elem().Set(elem)
+
+		return out, nil
+	}
+
+	switch {
+	case v.Kind() == reflect.String && target.Kind() != reflect.String:
+		if out, ok, err := parseString(v.String(), target); ok || err != nil {
+			if err != nil {
+				return reflect.Value{}, notConvertible(v, target, err.Error())
+			}
+
+			return out, nil
+		}
+
+	case target.Kind() == reflect.String && v.Kind() != reflect.String:
+		if s, ok, err := formatString(v); ok || err != nil {
+			if err != nil {
+				return reflect.Value{}, notConvertible(v, target, err.Error())
+			}
+
+			return reflect.ValueOf(s).Convert(target), nil
+		}
+
+	case isNumber(v.Kind()) && isNumber(target.Kind()):
+		return convertNumber(v, target)
+	}
+
+	switch target.Kind() {
+	case reflect.Slice:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			out := reflect.MakeSlice(target, v.Len(), v.Len())
+			return out, convertElems(v, out)
+		}
+
+	case reflect.Array:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			if v.Len() != target.Len() {
+				return reflect.Value{}, notConvertible(v, target, fmt.Sprintf("length %d is not %d", v.Len(), target.Len()))
+			}
+
+			out := reflect.New(target).Elem()
+			return out, convertElems(v, out)
+		}
+
+	case reflect.Map:
+		if v.Kind() == reflect.Map {
+			return convertMap(v, target)
+		}
+	}
+
+	if v.Kind() == target.Kind() && v.Type().ConvertibleTo(target) {
+		return v.Convert(target), nil
+	}
+
+	// Strings and []byte or []rune
+	if v.Kind() == reflect.String || target.Kind() == reflect.String {
+		if v.Type().ConvertibleTo(target) && (v.Kind() == reflect.Slice || target.Kind() == reflect.Slice) {
+			return v.Convert(target), nil
+		}
+	}
+
+	return reflect.Value{}, notConvertible(v, target, "")
+}
+
+func convertElems(v reflect.Value, out reflect.Value) error {
+	for i := 0; i < v.Len(); i++ {
+		elem, err := convertValue(v.Index(i), out.Type().Elem())
+		if err != nil {
+			return errors.Wrapf(err, "[%d]", i)
+		}
+
+		out.Index(i).Set(elem)
+	}
+
+	return nil
+}
+
+func convertMap(v reflect.Value, target reflect.Type) (reflect.Value, error) {
+	if v.IsNil() {
+		return reflect.Zero(target), nil
+	}
+
+	out := reflect.MakeMapWithSize(target, v.Len())
+	iter := v.MapRange()
+
+	for iter.Next() {
+		key, err := convertValue(iter.Key(), target.Key())
+		if err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "key %v", iter.Key())
+		}
+
+		value, err := convertValue(iter.Value(), target.Elem())
+		if err != nil {
+			return reflect.Value{}, errors.Wrapf(err, "[%v]", iter.Key())
+		}
+
+		out.SetMapIndex(key, value)
+	}
+
+	return out, nil
+}
+
+// parseString parses |s| into |target|, and reports whether |target| is supported
+func parseString(s string, target reflect.Type) (reflect.Value, bool, error) {
+	out := reflect.New(target).Elem()
+
+	switch {
+	case target == typeDuration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.SetInt(int64(d))
+		return out, true, nil
+
+	case target == typeTime:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.Set(reflect.ValueOf(t))
+		return out, true, nil
+
+	case reflect.PointerTo(target).Implements(typeTextUnmarshaler):
+		if err := out.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		return out, true, nil
+	}
+
+	switch k := target.Kind(); {
+	case k == reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.SetBool(b)
+
+	case isInt(k):
+		i, err := strconv.ParseInt(s, 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.SetInt(i)
+
+	case isUint(k):
+		u, err := strconv.ParseUint(s, 10, target.Bits())
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.SetUint(u)
+
+	case isFloat(k):
+		f, err := strconv.ParseFloat(s, target.Bits())
+		if err != nil {
+			return reflect.Value{}, true, err
+		}
+
+		out.SetFloat(f)
+
+	default:
+		return reflect.Value{}, false, nil
+	}
+
+	return out, true, nil
+}
+
+// formatString formats |v| as string, and reports whether |v| is supported
+func formatString(v reflect.Value) (string, bool, error) {
+	switch {
+	case v.Type() == typeDuration:
+		return time.Duration(v.Int()).String(), true, nil
+
+	case v.Type() == typeTime:
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), true, nil
+
+	case v.Type().Implements(typeTextMarshaler):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), true, err
+	}
+
+	switch k := v.Kind(); {
+	case k == reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case isInt(k):
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case isUint(k):
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case isFloat(k):
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true, nil
+	}
+
+	return "", false, nil
+}
+
+// convertNumber converts number |v| to numeric |target| only if lossless
+func convertNumber(v reflect.Value, target reflect.Type) (reflect.Value, error) {
+	const twoPow63, twoPow64 = 1 << 63, 1 << 64
+
+	out := reflect.New(target).Elem()
+	lossy := func() (reflect.Value, error) {
+		return reflect.Value{}, notConvertible(v, target, fmt.Sprintf("value %v is not representable", v.Interface()))
+	}
+
+	switch k := target.Kind(); {
+	case isInt(v.Kind()):
+		i := v.Int()
+		switch {
+		case isInt(k):
+			if out.OverflowInt(i) {
+				return lossy()
+			}
+
+			out.SetInt(i)
+
+		case isUint(k):
+			if i < 0 || out.OverflowUint(uint64(i)) {
+				return lossy()
+			}
+
+			out.SetUint(uint64(i))
+
+		case isFloat(k):
+			f := roundFloat(float64(i), target)
+			if f >= twoPow63 || int64(f) != i {
+				return lossy()
+			}
+
+			out.SetFloat(f)
+
+		default:
+			return lossy()
+		}
+
+	case isUint(v.Kind()):
+		u := v.Uint()
+		switch {
+		case isInt(k):
+			if u > math.MaxInt64 || out.OverflowInt(int64(u)) {
+				return lossy()
+			}
+
+			out.SetInt(int64(u))
+
+		case isUint(k):
+			if out.OverflowUint(u) {
+				return lossy()
+			}
+
+			out.SetUint(u)
+
+		case isFloat(k):
+			f := roundFloat(float64(u), target)
+			if f >= twoPow64 || uint64(f) != u {
+				return lossy()
+			}
+
+			out.SetFloat(f)
+
+		default:
+			return lossy()
+		}
+
+	case isFloat(v.Kind()):
+		f := v.Float()
+		integral := f == math.Trunc(f) && !math.IsInf(f, 0)
+
+		switch {
+		case isInt(k):
+			if !integral || f < -twoPow63 || f >= twoPow63 || out.OverflowInt(int64(f)) {
+				return lossy()
+			}
+
+			out.SetInt(int64(f))
+
+		case isUint(k):
+			if !integral || f < 0 || f >= twoPow64 || out.OverflowUint(uint64(f)) {
+				return lossy()
+			}
+
+			out.SetUint(uint64(f))
+
+		case isFloat(k):
+			if rounded := roundFloat(f, target); rounded != f && !math.IsNaN(f) {
+				return lossy()
+			}
+
+			out.SetFloat(f)
+
+		default:
+			return lossy()
+		}
+
+	default:
+		// Complex numbers
+		if !v.Type().ConvertibleTo(target) || v.Kind() != k {
+			return lossy()
+		}
+
+		c := v.Complex()
+		if out.OverflowComplex(c) {
+			return lossy()
+		}
+
+		out.SetComplex(c)
+	}
+
+	return out, nil
+}
+
+// roundFloat rounds |f| to the precision of float |target|
+func roundFloat(f float64, target reflect.Type) float64 {
+	if target.Kind() == reflect.Float32 {
+		return float64(float32(f))
+	}
+
+	return f
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || isFloat(k) || k == reflect.Complex64 || k == reflect.Complex128
+}
@@ -0,0 +1,154 @@
+package gsl
+
+import (
+	"math"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestConvertValue(t *testing.T) {
+	type myString string
+
+	five := 5
+	addr := netip.MustParseAddr("10.0.0.1")
+
+	type test struct {
+		v        interface{}
+		target   reflect.Type
+		expected interface{}
+		err      bool
+	}
+
+	tests := []test{
+		// Nil values
+		{v: nil, target: reflect.TypeFor[*int](), expected: (*int)(nil)},
+		{v: nil, target: reflect.TypeFor[[]int](), expected: []int(nil)},
+		{v: (*int)(nil), target: reflect.TypeFor[interface{}](), expected: nil},
+		{v: nil, target: reflect.TypeFor[int](), err: true},
+		{v: (*int)(nil), target: reflect.TypeFor[int](), err: true},
+
+		// Numbers
+		{v: float64(3), target: reflect.TypeFor[int](), expected: 3},
+		{v: float64(3.5), target: reflect.TypeFor[int](), err: true},
+		{v: 300, target: reflect.TypeFor[uint8](), err: true},
+		{v: -1, target: reflect.TypeFor[uint](), err: true},
+		{v: uint64(math.MaxUint64), target: reflect.TypeFor[int64](), err: true},
+		{v: int64(1<<53 + 1), target: reflect.TypeFor[float64](), err: true},
+		{v: int64(1 << 53), target: reflect.TypeFor[float64](), expected: float64(1 << 53)},
+		{v: 0.1, target: reflect.TypeFor[float32](), err: true},
+		{v: 0.5, target: reflect.TypeFor[float32](), expected: float32(0.5)},
+		{v: math.Inf(1), target: reflect.TypeFor[int64](), err: true},
+		{v: &five, target: reflect.TypeFor[int8](), expected: int8(5)},
+		{v: int8(5), target: reflect.TypeFor[*int](), expected: &five},
+
+		// Strings
+		{v: 69, target: reflect.TypeFor[string](), expected: "69"},
+		{v: 1.5, target: reflect.TypeFor[myString](), expected: myString("1.5")},
+		{v: "69", target: reflect.TypeFor[uint8](), expected: uint8(69)},
+		{v: "690", target: reflect.TypeFor[uint8](), err: true},
+		{v: "true", target: reflect.TypeFor[bool](), expected: true},
+		{v: "yes", target: reflect.TypeFor[bool](), err: true},
+		{v: "1m30s", target: reflect.TypeFor[time.Duration](), expected: 90 * time.Second},
+		{v: 90 * time.Second, target: reflect.TypeFor[string](), expected: "1m30s"},
+		{v: "2024-01-02T03:04:05Z", target: reflect.TypeFor[time.Time](), expected: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{v: "10.0.0.1", target: reflect.TypeFor[netip.Addr](), expected: addr},
+		{v: addr, target: reflect.TypeFor[string](), expected: "10.0.0.1"},
+		{v: "foo", target: reflect.TypeFor[[]byte](), expected: []byte("foo")},
+		{v: []byte("foo"), target: reflect.TypeFor[string](), expected: "foo"},
+		{v: true, target: reflect.TypeFor[int](), err: true},
+		{v: struct{}{}, target: reflect.TypeFor[string](), err: true},
+
+		// Containers
+		{v: []interface{}{1.0, "2", 3}, target: reflect.TypeFor[[]int](), expected: []int{1, 2, 3}},
+		{v: []interface{}{1, 2}, target: reflect.TypeFor[[2]string](), expected: [2]string{"1", "2"}},
+		{v: []interface{}{1, 2}, target: reflect.TypeFor[[3]string](), err: true},
+		{v: []interface{}{1, 1.5}, target: reflect.TypeFor[[]int](), err: true},
+		{
+			v:        map[string]interface{}{"1": "1s", "2": []interface{}{"2m"}[0]},
+			target:   reflect.TypeFor[map[int]time.Duration](),
+			expected: map[int]time.Duration{1: time.Second, 2: 2 * time.Minute},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual, err := ConvertValue(test.v, test.target)
+		if test.err {
+			if !errors.Is(err, ErrNotConvertible) {
+				t.Fatalf("[%d] expecting ErrNotConvertible converting %v to %s, got %v", i, test.v, test.target, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %v", i, err)
+		}
+
+		if actual.Type() != test.target {
+			t.Fatalf("[%d] unexpected type %s, expecting %s", i, actual.Type(), test.target)
+		}
+
+		if !reflect.DeepEqual(test.expected, actual.Interface()) {
+			t.Logf("Expecting %#v", test.expected)
+			t.Fatalf("[%d] unexpected value %#v", i, actual.Interface())
+		}
+	}
+}
+
+func TestConvertValueErrorPath(t *testing.T) {
+	_, err := InterfaceTo[map[string][]int](map[string]interface{}{
+		"a": []interface{}{1, 2},
+		"b": []interface{}{3, "four"},
+	})
+
+	if !errors.Is(err, ErrNotConvertible) {
+		t.Fatalf("expecting ErrNotConvertible, got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "[b]: [1]") {
+		t.Fatalf("error does not report element path: %s", err.Error())
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	type celsius float64
+	type fahrenheit float64
+
+	RegisterConverter(func(c celsius) (fahrenheit, error) {
+		return fahrenheit(c*9/5 + 32), nil
+	})
+
+	RegisterConverter(func(s string) (celsius, error) {
+		if !strings.HasSuffix(s, "C") {
+			return 0, errors.New("missing unit")
+		}
+
+		f, err := strconv.ParseFloat(strings.TrimSuffix(s, "C"), 64)
+		return celsius(f), err
+	})
+
+	temps, err := InterfaceTo[[]fahrenheit]([]celsius{0, 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(temps, []fahrenheit{32, 212}) {
+		t.Fatalf("unexpected temperatures %v", temps)
+	}
+
+	if c, err := InterfaceTo[celsius]("36.6C"); err != nil || c != 36.6 {
+		t.Fatalf("unexpected result %v, err %v", c, err)
+	}
+
+	if _, err := InterfaceTo[celsius]("36.6"); !errors.Is(err, ErrNotConvertible) {
+		t.Fatalf("expecting ErrNotConvertible from converter error, got %v", err)
+	}
+}
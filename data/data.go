@@ -1,6 +1,8 @@
 package data
 
 import (
+	"iter"
+
 	"golang.org/x/exp/constraints"
 )
 
@@ -17,6 +19,12 @@ type Wrapper[T any] interface {
 	Getter[T]
 }
 
+// Iterable is implemented by data structures that can be
+// iterated with range-over-func, e.g. `for x := range l.All()`.
+type Iterable[T any] interface {
+	All() iter.Seq[T]
+}
+
 type Set[T comparable] interface {
 	HasDuplicate(x T) bool
 }
@@ -0,0 +1,98 @@
+package list
+
+import (
+	"container/heap"
+	"iter"
+
+	"github.com/soyart/gsl/data"
+)
+
+// All returns an iterator over the stack in pop order (last in, first out).
+// The stack is not modified.
+func (s *StackImpl[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(*s) - 1; i >= 0; i-- {
+			if !yield((*s)[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the queue in pop order (first in, first out).
+// The queue is not modified.
+func (s *QueueImpl[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range *s {
+			if !yield((*s)[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the set list in pop order.
+// The set list is not modified.
+func (s *SetListImpl[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.length - 1; i >= 0; i-- {
+			if !yield(s.haystack[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the queue in priority order.
+// Items are lazily popped from a copy of the queue, so the queue is not modified.
+func (q *PriorityQueue[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		clone := &PriorityQueue[T]{
+			Items:    make([]data.Getter[T], len(q.Items)),
+			LessFunc: q.LessFunc,
+		}
+
+		copy(clone.Items, q.Items)
+		heap.Init(clone)
+
+		for clone.Len() > 0 {
+			item := heap.Pop(clone).(data.Getter[T])
+			if !yield(item.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over a snapshot of the inner list taken under read lock,
+// so that the lock is not held while iterating. If the inner list does not
+// implement data.Iterable[T], the iterator yields nothing.
+func (w *SafeListWrapper[T, L]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var snapshot []T
+
+		w.mut.RLock()
+		if inner, ok := any(w.inner).(data.Iterable[T]); ok {
+			for x := range inner.All() {
+				snapshot = append(snapshot, x)
+			}
+		}
+		w.mut.RUnlock()
+
+		for i := range snapshot {
+			if !yield(snapshot[i]) {
+				return
+			}
+		}
+	}
+}
+
+// All returns the inner list's iterator. If the inner list does not
+// implement data.Iterable[T], the iterator yields nothing.
+func (s *SetListWrapper[T, L]) All() iter.Seq[T] {
+	if inner, ok := any(s.inner).(data.Iterable[T]); ok {
+		return inner.All()
+	}
+
+	return func(func(T) bool) {}
+}
@@ -0,0 +1,76 @@
+package list
+
+import (
+	"container/heap"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/soyart/gsl/data"
+)
+
+func TestAll(t *testing.T) {
+	values := []int{3, 1, 2}
+
+	stack := NewStack[int]()
+	stack.PushSlice(values)
+
+	queue := NewQueue[int]()
+	queue.PushSlice(values)
+
+	setList := ToSetList([]int{3, 1, 1, 2, 3}).(*SetListImpl[int])
+	setList.Pop()
+
+	type test struct {
+		name     string
+		list     data.Iterable[int]
+		expected []int
+	}
+
+	tests := []test{
+		{name: "stack", list: stack, expected: []int{2, 1, 3}},
+		{name: "queue", list: queue, expected: []int{3, 1, 2}},
+		{name: "setlist", list: setList, expected: []int{1, 3}},
+		{name: "safe stack", list: WrapSafeList[int](stack), expected: []int{2, 1, 3}},
+		{name: "set wrapper", list: WrapSetListKeepInner[int](queue), expected: []int{3, 1, 2}},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual := slices.Collect(test.list.All())
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%s] unexpected values %v", test.name, actual)
+		}
+	}
+
+	// Iterating must not modify the lists
+	if stack.Len() != 3 || queue.Len() != 3 || setList.Len() != 2 {
+		t.Fatalf("lists modified by All")
+	}
+}
+
+func TestAllPriorityQueue(t *testing.T) {
+	pq := NewPriorityQueue[int](data.Ascending)
+	for _, x := range []int{5, 1, 4, 2, 3} {
+		heap.Push(pq, data.NewGetter(x))
+	}
+
+	expected := []int{1, 2, 3, 4, 5}
+	if actual := slices.Collect(pq.All()); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected values: expecting %v, got %v", expected, actual)
+	}
+
+	if pq.Len() != 5 {
+		t.Fatalf("priority queue modified by All")
+	}
+
+	for x := range pq.All() {
+		if x != 1 {
+			t.Fatalf("unexpected first value %d", x)
+		}
+
+		break
+	}
+}
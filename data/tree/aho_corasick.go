@@ -0,0 +1,179 @@
+package tree
+
+import (
+	"iter"
+)
+
+// AhoCorasick finds all occurrences of many patterns in a text in one pass,
+// in O(len(text) + matches) time, using the Aho-Corasick automaton
+// compiled from a Trie of the patterns.
+//
+// Offsets are in bytes, and because patterns and text are matched byte-wise,
+// valid UTF-8 patterns never match in the middle of a rune of a valid UTF-8 text.
+//
+// AhoCorasick is immutable, and safe for concurrent use.
+type AhoCorasick struct {
+	patterns []string
+	states   []acState
+}
+
+// AhoCorasickMatch is an occurrence of pattern Pattern (index of the pattern
+// given to NewAhoCorasick) at text[Start:End].
+type AhoCorasickMatch struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+type acState struct {
+	edges []acEdge // Sorted by label
+	fail  int
+
+	// pattern is the index of the pattern ending at this state, or -1
+	pattern int
+
+	// output is the nearest state on the fail chain with a pattern, or -1
+	output int
+}
+
+type acEdge struct {
+	label byte
+	next  int
+}
+
+// NewAhoCorasick compiles |patterns| into an automaton.
+// Empty patterns never match, and duplicate patterns
+// are reported with the index of their first occurrence.
+func NewAhoCorasick(patterns ...string) *AhoCorasick {
+	trie := NewTrie[int]()
+	for i := len(patterns) - 1; i >= 0; i-- {
+		if patterns[i] != "" {
+			trie.Insert(patterns[i], i)
+		}
+	}
+
+	// Copy patterns, which are used for match offsets
+	a := &AhoCorasick{patterns: append([]string(nil), patterns...)}
+
+	// Number trie nodes in BFS order, so that fail links
+	// always point to states computed earlier
+	nodes := []*trieNode[int]{&trie.root}
+	a.states = []acState{{pattern: -1, output: -1}}
+
+	for id := 0; id < len(nodes); id++ {
+		node := nodes[id]
+		if node.ok {
+			a.states[id].pattern = node.value
+		}
+
+		for _, edge := range node.edges {
+			next := len(nodes)
+			nodes = append(nodes, edge.node)
+			a.states = append(a.states, acState{pattern: -1, output: -1})
+			a.states[id].edges = append(a.states[id].edges, acEdge{label: edge.label, next: next})
+
+			if id == 0 {
+				continue
+			}
+
+			a.states[next].fail = a.transition(a.states[id].fail, edge.label)
+		}
+
+		if fail := a.states[id].fail; id != 0 {
+			if a.states[fail].pattern != -1 {
+				a.states[id].output = fail
+			} else {
+				a.states[id].output = a.states[fail].output
+			}
+		}
+	}
+
+	return a
+}
+
+// next returns the goto target of |state| on |label|, or -1 if there is none
+func (a *AhoCorasick) next(state int, label byte) int {
+	edges := a.states[state].edges
+
+	lo, hi := 0, len(edges)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch {
+		case edges[mid].label == label:
+			return edges[mid].next
+		case edges[mid].label < label:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return -1
+}
+
+// transition follows fail links from |state| until |label| can be consumed
+func (a *AhoCorasick) transition(state int, label byte) int {
+	for {
+		if next := a.next(state, label); next != -1 {
+			return next
+		}
+
+		if state == 0 {
+			return 0
+		}
+
+		state = a.states[state].fail
+	}
+}
+
+// Patterns returns a copy of the patterns given to NewAhoCorasick.
+func (a *AhoCorasick) Patterns() []string {
+	return append([]string(nil), a.patterns...)
+}
+
+// Matches returns an iterator over all, possibly overlapping, matches in |text|,
+// ordered by End, and then from the longest to the shortest match.
+func (a *AhoCorasick) Matches(text string) iter.Seq[AhoCorasickMatch] {
+	return func(yield func(AhoCorasickMatch) bool) {
+		state := 0
+		for i := 0; i < len(text); i++ {
+			state = a.transition(state, text[i])
+
+			for out := state; out != -1; out = a.states[out].output {
+				pattern := a.states[out].pattern
+				if pattern == -1 {
+					continue
+				}
+
+				match := AhoCorasickMatch{
+					Pattern: pattern,
+					Start:   i + 1 - len(a.patterns[pattern]),
+					End:     i + 1,
+				}
+
+				if !yield(match) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// FindAll returns all, possibly overlapping, matches in |text|. See Matches.
+func (a *AhoCorasick) FindAll(text string) []AhoCorasickMatch {
+	var matches []AhoCorasickMatch
+	for match := range a.Matches(text) {
+		matches = append(matches, match)
+	}
+
+	return matches
+}
+
+// ContainsAny returns true if any pattern occurs in |text|.
+func (a *AhoCorasick) ContainsAny(text string) bool {
+	for range a.Matches(text) {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package tree
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	a := NewAhoCorasick("he", "she", "his", "hers", "", "he", "ไทย")
+
+	expected := []AhoCorasickMatch{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+		{Pattern: 6, Start: 7, End: 16},
+	}
+
+	if actual := a.FindAll("ushers ไทย"); !reflect.DeepEqual(expected, actual) {
+		t.Logf("Expecting %+v", expected)
+		t.Fatalf("unexpected matches %+v", actual)
+	}
+
+	if !a.ContainsAny("this") || a.ContainsAny("xyz") || a.ContainsAny("") {
+		t.Fatalf("unexpected ContainsAny result")
+	}
+
+	// Mutating the given patterns or Patterns() does not affect the automaton
+	patterns := []string{"abc"}
+	a = NewAhoCorasick(patterns...)
+	patterns[0] = "abcdef"
+	a.Patterns()[0] = "abcdef"
+
+	if matches := a.FindAll("xabc"); !reflect.DeepEqual(matches, []AhoCorasickMatch{{Pattern: 0, Start: 1, End: 4}}) {
+		t.Fatalf("unexpected matches after mutating patterns %+v", matches)
+	}
+
+	if NewAhoCorasick().FindAll("abc") != nil {
+		t.Fatalf("unexpected matches without patterns")
+	}
+}
+
+func TestAhoCorasickRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(4, 20))
+	randomString := func(n int) string {
+		var b strings.Builder
+		for ; n > 0; n-- {
+			b.WriteByte("ab"[rng.IntN(2)])
+		}
+
+		return b.String()
+	}
+
+	for round := 0; round < 50; round++ {
+		patterns := make([]string, 1+rng.IntN(10))
+		for i := range patterns {
+			patterns[i] = randomString(1 + rng.IntN(5))
+		}
+
+		text := randomString(200)
+		a := NewAhoCorasick(patterns...)
+
+		// Brute force, with the same order and duplicate handling
+		var expected []AhoCorasickMatch
+		for end := 1; end <= len(text); end++ {
+			for start := 0; start < end; start++ {
+				for i, pattern := range patterns {
+					if text[start:end] == pattern {
+						expected = append(expected, AhoCorasickMatch{Pattern: i, Start: start, End: end})
+						break
+					}
+				}
+			}
+		}
+
+		if actual := a.FindAll(text); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("[%d] unexpected matches for patterns %q", round, patterns)
+		}
+	}
+}
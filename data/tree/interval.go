@@ -0,0 +1,368 @@
+package tree
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is a closed interval [Low, High].
+type Interval[N constraints.Ordered] struct {
+	Low  N
+	High N
+}
+
+func (i Interval[N]) IsValid() bool {
+	return i.Low <= i.High
+}
+
+// Contains returns whether |point| is in |i|.
+func (i Interval[N]) Contains(point N) bool {
+	return i.Low <= point && point <= i.High
+}
+
+// Overlaps returns whether |i| and |other| share any point.
+func (i Interval[N]) Overlaps(other Interval[N]) bool {
+	return i.Low <= other.High && other.Low <= i.High
+}
+
+func (i Interval[N]) cmp(other Interval[N]) int {
+	switch {
+	case i.Low < other.Low:
+		return -1
+	case i.Low > other.Low:
+		return 1
+	case i.High < other.High:
+		return -1
+	case i.High > other.High:
+		return 1
+	}
+
+	return 0
+}
+
+// IntervalTree is an AVL tree of intervals ordered by (Low, High),
+// with each node augmented with the maximum High in its subtree.
+// Insert, Remove, Find and AnyOverlap take O(log n) time.
+//
+// Overlaps and Stab skip subtrees that cannot contain overlaps, but take
+// O(min(n, k log n)) time to find all k overlaps in the worst case, not O(log n + k):
+// intervals starting before the query are not contiguous in the tree, so the search
+// may visit O(log n) non-overlapping ancestors for each of them. Intervals starting
+// within the query are contiguous, and are found in O(log n + k) time.
+//
+// IntervalTree implements BinaryTreeBasic[Interval[N]], and its nodes implement BinaryTreeNode.
+// Identical intervals are stored once. The zero value is an empty tree ready to use.
+type IntervalTree[N constraints.Ordered] struct {
+	root   *IntervalTreeNode[N]
+	length int
+}
+
+// IntervalTreeNode is a node of IntervalTree, and implements BinaryTreeNode[Interval[N]].
+type IntervalTreeNode[N constraints.Ordered] struct {
+	interval Interval[N]
+	max      N
+	height   int
+
+	left  *IntervalTreeNode[N]
+	right *IntervalTreeNode[N]
+}
+
+func NewIntervalTree[N constraints.Ordered]() *IntervalTree[N] {
+	return new(IntervalTree[N])
+}
+
+// Root returns the root node, or nil if the tree is empty.
+func (t *IntervalTree[N]) Root() BinaryTreeNode[Interval[N]] {
+	if t.root == nil {
+		return nil
+	}
+
+	return t.root
+}
+
+func (t *IntervalTree[N]) Len() int {
+	return t.length
+}
+
+func (t *IntervalTree[N]) IsEmpty() bool {
+	return t.length == 0
+}
+
+// Insert inserts |interval|, and returns false if it already exists
+// or is invalid (Low > High).
+func (t *IntervalTree[N]) Insert(interval Interval[N]) bool {
+	if !interval.IsValid() {
+		return false
+	}
+
+	var inserted bool
+	t.root = intervalInsert(t.root, interval, &inserted)
+
+	if inserted {
+		t.length++
+	}
+
+	return inserted
+}
+
+// Remove removes |interval|, and returns false if it does not exist.
+func (t *IntervalTree[N]) Remove(interval Interval[N]) bool {
+	var removed bool
+	t.root = intervalRemove(t.root, interval, &removed)
+
+	if removed {
+		t.length--
+	}
+
+	return removed
+}
+
+// Find returns whether |interval| is in the tree.
+func (t *IntervalTree[N]) Find(interval Interval[N]) bool {
+	curr := t.root
+	for curr != nil {
+		switch c := interval.cmp(curr.interval); {
+		case c < 0:
+			curr = curr.left
+		case c > 0:
+			curr = curr.right
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Stab returns intervals containing |point|, ordered by (Low, High).
+func (t *IntervalTree[N]) Stab(point N) []Interval[N] {
+	return t.Overlaps(Interval[N]{Low: point, High: point})
+}
+
+// Overlaps returns intervals overlapping |query|, ordered by (Low, High),
+// in O(min(n, k log n)) time (see IntervalTree).
+func (t *IntervalTree[N]) Overlaps(query Interval[N]) []Interval[N] {
+	var result []Interval[N]
+	intervalOverlaps(t.root, query, func(interval Interval[N]) {
+		result = append(result, interval)
+	})
+
+	return result
+}
+
+// AnyOverlap returns an interval overlapping |query| in O(log n) time,
+// and false if there is none.
+func (t *IntervalTree[N]) AnyOverlap(query Interval[N]) (Interval[N], bool) {
+	curr := t.root
+	for curr != nil {
+		if curr.interval.Overlaps(query) {
+			return curr.interval, true
+		}
+
+		// If the left subtree has no interval reaching query.Low,
+		// neither does it have any overlap
+		if curr.left != nil && curr.left.max >= query.Low {
+			curr = curr.left
+			continue
+		}
+
+		curr = curr.right
+	}
+
+	return Interval[N]{}, false
+}
+
+// All returns an iterator over intervals ordered by (Low, High).
+func (t *IntervalTree[N]) All() iter.Seq[Interval[N]] {
+	return func(yield func(Interval[N]) bool) {
+		var stack []*IntervalTreeNode[N]
+		curr := t.root
+
+		for curr != nil || len(stack) != 0 {
+			for curr != nil {
+				stack = append(stack, curr)
+				curr = curr.left
+			}
+
+			curr = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if !yield(curr.interval) {
+				return
+			}
+
+			curr = curr.right
+		}
+	}
+}
+
+func (n *IntervalTreeNode[N]) Value() Interval[N] { return n.interval }
+
+// Max returns the maximum High of intervals in the subtree rooted at |n|.
+func (n *IntervalTreeNode[N]) Max() N { return n.max }
+
+// Left returns the left child, or nil if there is none.
+func (n *IntervalTreeNode[N]) Left() BinaryTreeNode[Interval[N]] {
+	if n.left == nil {
+		return nil
+	}
+
+	return n.left
+}
+
+// Right returns the right child, or nil if there is none.
+func (n *IntervalTreeNode[N]) Right() BinaryTreeNode[Interval[N]] {
+	if n.right == nil {
+		return nil
+	}
+
+	return n.right
+}
+
+func (n *IntervalTreeNode[N]) IsNull() bool {
+	return n == nil
+}
+
+func (n *IntervalTreeNode[N]) IsLeaf() bool {
+	return n.left == nil && n.right == nil
+}
+
+func intervalOverlaps[N constraints.Ordered](node *IntervalTreeNode[N], query Interval[N], f func(Interval[N])) {
+	// No interval in this subtree reaches query.Low
+	if node == nil || node.max < query.Low {
+		return
+	}
+
+	intervalOverlaps(node.left, query, f)
+
+	if node.interval.Overlaps(query) {
+		f(node.interval)
+	}
+
+	// Intervals in the right subtree start after query.High
+	if node.interval.Low > query.High {
+		return
+	}
+
+	intervalOverlaps(node.right, query, f)
+}
+
+func intervalInsert[N constraints.Ordered](node *IntervalTreeNode[N], interval Interval[N], inserted *bool) *IntervalTreeNode[N] {
+	if node == nil {
+		*inserted = true
+		return &IntervalTreeNode[N]{interval: interval, max: interval.High, height: 1}
+	}
+
+	switch c := interval.cmp(node.interval); {
+	case c < 0:
+		node.left = intervalInsert(node.left, interval, inserted)
+	case c > 0:
+		node.right = intervalInsert(node.right, interval, inserted)
+	default:
+		return node
+	}
+
+	return intervalRebalance(node)
+}
+
+func intervalRemove[N constraints.Ordered](node *IntervalTreeNode[N], interval Interval[N], removed *bool) *IntervalTreeNode[N] {
+	if node == nil {
+		return nil
+	}
+
+	switch c := interval.cmp(node.interval); {
+	case c < 0:
+		node.left = intervalRemove(node.left, interval, removed)
+	case c > 0:
+		node.right = intervalRemove(node.right, interval, removed)
+	default:
+		*removed = true
+
+		if node.left == nil {
+			return node.right
+		}
+
+		if node.right == nil {
+			return node.left
+		}
+
+		// Replace with the inorder successor
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+
+		var ok bool
+		node.interval = successor.interval
+		node.right = intervalRemove(node.right, successor.interval, &ok)
+	}
+
+	return intervalRebalance(node)
+}
+
+func intervalHeight[N constraints.Ordered](node *IntervalTreeNode[N]) int {
+	if node == nil {
+		return 0
+	}
+
+	return node.height
+}
+
+// intervalUpdate recomputes height and max of |node| from its children
+func intervalUpdate[N constraints.Ordered](node *IntervalTreeNode[N]) {
+	node.height = 1 + max(intervalHeight(node.left), intervalHeight(node.right))
+	node.max = node.interval.High
+
+	if node.left != nil && node.left.max > node.max {
+		node.max = node.left.max
+	}
+
+	if node.right != nil && node.right.max > node.max {
+		node.max = node.right.max
+	}
+}
+
+func intervalRotateLeft[N constraints.Ordered](node *IntervalTreeNode[N]) *IntervalTreeNode[N] {
+	pivot := node.right
+	node.right = pivot.left
+	pivot.left = node
+
+	intervalUpdate(node)
+	intervalUpdate(pivot)
+
+	return pivot
+}
+
+func intervalRotateRight[N constraints.Ordered](node *IntervalTreeNode[N]) *IntervalTreeNode[N] {
+	pivot := node.left
+	node.left = pivot.right
+	pivot.right = node
+
+	intervalUpdate(node)
+	intervalUpdate(pivot)
+
+	return pivot
+}
+
+func intervalRebalance[N constraints.Ordered](node *IntervalTreeNode[N]) *IntervalTreeNode[N] {
+	intervalUpdate(node)
+
+	switch balance := intervalHeight(node.left) - intervalHeight(node.right); {
+	case balance > 1:
+		if intervalHeight(node.left.left) < intervalHeight(node.left.right) {
+			node.left = intervalRotateLeft(node.left)
+		}
+
+		return intervalRotateRight(node)
+
+	case balance < -1:
+		if intervalHeight(node.right.right) < intervalHeight(node.right.left) {
+			node.right = intervalRotateRight(node.right)
+		}
+
+		return intervalRotateLeft(node)
+	}
+
+	return node
+}
@@ -0,0 +1,235 @@
+package tree
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestIntervalTree(t *testing.T) {
+	tree := NewIntervalTree[int]()
+	intervals := []Interval[int]{
+		{15, 20}, {10, 30}, {17, 19}, {5, 20}, {12, 15}, {30, 40},
+	}
+
+	for _, interval := range intervals {
+		if !tree.Insert(interval) {
+			t.Fatalf("Insert returned false for new interval %v", interval)
+		}
+	}
+
+	if tree.Insert(Interval[int]{10, 30}) {
+		t.Fatalf("Insert returned true for existing interval")
+	}
+
+	if tree.Insert(Interval[int]{3, 1}) {
+		t.Fatalf("Insert returned true for invalid interval")
+	}
+
+	if tree.Len() != len(intervals) {
+		t.Fatalf("unexpected length %d", tree.Len())
+	}
+
+	type test struct {
+		query    Interval[int]
+		expected []Interval[int]
+	}
+
+	tests := []test{
+		{query: Interval[int]{18, 18}, expected: []Interval[int]{{5, 20}, {10, 30}, {15, 20}, {17, 19}}},
+		{query: Interval[int]{0, 4}, expected: nil},
+		{query: Interval[int]{31, 50}, expected: []Interval[int]{{30, 40}}},
+		{query: Interval[int]{6, 11}, expected: []Interval[int]{{5, 20}, {10, 30}}},
+		{query: Interval[int]{41, 41}, expected: nil},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual := tree.Overlaps(test.query)
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%d] unexpected overlaps %v", i, actual)
+		}
+
+		_, found := tree.AnyOverlap(test.query)
+		if found != (test.expected != nil) {
+			t.Fatalf("[%d] unexpected AnyOverlap result %v", i, found)
+		}
+	}
+
+	if stabbed := tree.Stab(30); !reflect.DeepEqual(stabbed, []Interval[int]{{10, 30}, {30, 40}}) {
+		t.Fatalf("unexpected stabbed intervals %v", stabbed)
+	}
+
+	// Nodes work with BinaryTreeNode helpers
+	leftmost, _ := DigLeft(tree.Root())
+	if leftmost.Value() != (Interval[int]{5, 20}) {
+		t.Fatalf("unexpected leftmost interval %v", leftmost.Value())
+	}
+
+	if !tree.Remove(Interval[int]{10, 30}) || tree.Remove(Interval[int]{10, 30}) || tree.Find(Interval[int]{10, 30}) {
+		t.Fatalf("unexpected Remove result")
+	}
+
+	if stabbed := tree.Stab(25); stabbed != nil {
+		t.Fatalf("unexpected stabbed intervals after removal %v", stabbed)
+	}
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree := new(IntervalTree[int])
+	set := make(map[Interval[int]]bool)
+
+	for i := 0; i < 2000; i++ {
+		low := rng.IntN(1000)
+		interval := Interval[int]{Low: low, High: low + rng.IntN(50)}
+
+		// Remove about a third of the time
+		if rng.IntN(3) == 0 {
+			if tree.Remove(interval) != set[interval] {
+				t.Fatalf("unexpected Remove result for %v", interval)
+			}
+
+			delete(set, interval)
+			continue
+		}
+
+		if tree.Insert(interval) == set[interval] {
+			t.Fatalf("unexpected Insert result for %v", interval)
+		}
+
+		set[interval] = true
+	}
+
+	if tree.Len() != len(set) {
+		t.Fatalf("unexpected length %d, expecting %d", tree.Len(), len(set))
+	}
+
+	checkIntervalNode(t, tree.root)
+
+	var all []Interval[int]
+	for interval := range set {
+		all = append(all, interval)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].cmp(all[j]) < 0
+	})
+
+	if actual := slices.Collect(tree.All()); !reflect.DeepEqual(all, actual) {
+		t.Fatalf("unexpected intervals from All")
+	}
+
+	for i := 0; i < 200; i++ {
+		low := rng.IntN(1100) - 50
+		query := Interval[int]{Low: low, High: low + rng.IntN(20)}
+
+		var expected []Interval[int]
+		for _, interval := range all {
+			if interval.Overlaps(query) {
+				expected = append(expected, interval)
+			}
+		}
+
+		if actual := tree.Overlaps(query); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("unexpected overlaps for %v: expecting %v, got %v", query, expected, actual)
+		}
+
+		overlap, found := tree.AnyOverlap(query)
+		if found != (expected != nil) || (found && !overlap.Overlaps(query)) {
+			t.Fatalf("unexpected AnyOverlap result for %v: %v %v", query, overlap, found)
+		}
+	}
+}
+
+func TestIntervalTreeRemoveRotations(t *testing.T) {
+	tree := NewIntervalTree[int]()
+
+	// Some intervals are long, so that max moves with their nodes during rotations
+	var intervals []Interval[int]
+	for i := 0; i < 64; i++ {
+		interval := Interval[int]{Low: i, High: i + 1}
+		if i%7 == 0 {
+			interval.High = 1000 - i
+		}
+
+		tree.Insert(interval)
+		intervals = append(intervals, interval)
+	}
+
+	// Removing every other interval, then the rest in reverse, unbalances the tree
+	var order []Interval[int]
+	for i := 0; i < len(intervals); i += 2 {
+		order = append(order, intervals[i])
+	}
+
+	for i := len(intervals) - 1; i > 0; i -= 2 {
+		order = append(order, intervals[i])
+	}
+
+	set := make(map[Interval[int]]bool)
+	for _, interval := range intervals {
+		set[interval] = true
+	}
+
+	query := Interval[int]{Low: 500, High: 500}
+	var rootChanges int
+
+	for _, removed := range order {
+		root := tree.root
+		if !tree.Remove(removed) {
+			t.Fatalf("Remove returned false for %v", removed)
+		}
+
+		delete(set, removed)
+		if tree.root != root {
+			rootChanges++
+		}
+
+		checkIntervalNode(t, tree.root)
+
+		var expected []Interval[int]
+		for _, interval := range intervals {
+			if set[interval] && interval.Overlaps(query) {
+				expected = append(expected, interval)
+			}
+		}
+
+		if actual := tree.Overlaps(query); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("unexpected overlaps after removing %v: expecting %v, got %v", removed, expected, actual)
+		}
+	}
+
+	if !tree.IsEmpty() || rootChanges == 0 {
+		t.Fatalf("unexpected tree after removals: length %d, root changed %d times", tree.Len(), rootChanges)
+	}
+}
+
+// checkIntervalNode checks AVL balance and max augmentation, and returns subtree height
+func checkIntervalNode(t *testing.T, node *IntervalTreeNode[int]) int {
+	if node == nil {
+		return 0
+	}
+
+	hl, hr := checkIntervalNode(t, node.left), checkIntervalNode(t, node.right)
+	if hl-hr > 1 || hr-hl > 1 {
+		t.Fatalf("unbalanced node %v: heights %d and %d", node.interval, hl, hr)
+	}
+
+	expectedMax := node.interval.High
+	for _, child := range []*IntervalTreeNode[int]{node.left, node.right} {
+		if child != nil && child.max > expectedMax {
+			expectedMax = child.max
+		}
+	}
+
+	if node.max != expectedMax {
+		t.Fatalf("bad max for node %v: %d, expecting %d", node.interval, node.max, expectedMax)
+	}
+
+	return 1 + max(hl, hr)
+}
@@ -0,0 +1,71 @@
+package tree
+
+import (
+	"iter"
+
+	"github.com/soyart/gsl/data"
+)
+
+// All returns an iterator over values of the subtree rooted at |n| in order.
+// Null nodes, e.g. the root of an empty tree, are skipped.
+func (n *BinaryTreeNodeWrapper[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var stack []*BinaryTreeNodeWrapper[T]
+		curr := n
+
+		for curr != nil || len(stack) != 0 {
+			for curr != nil {
+				stack = append(stack, curr)
+				curr = curr.left
+			}
+
+			curr = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if !curr.IsNull() && !yield(curr.value) {
+				return
+			}
+
+			curr = curr.right
+		}
+	}
+}
+
+// All returns an iterator over the tree's values in ascending order.
+func (b *Bst[T]) All() iter.Seq[T] {
+	return b.Root.All()
+}
+
+// All returns an iterator over the tree's values in ascending order.
+func (b *BstCmp[T]) All() iter.Seq[T] {
+	return b.Root.All()
+}
+
+// All returns the inner tree's iterator. If the inner tree does not
+// implement data.Iterable[T], the iterator yields nothing.
+func (b *BstCount[T]) All() iter.Seq[T] {
+	if inner, ok := b.BinaryTreeBasic.(data.Iterable[T]); ok {
+		return inner.All()
+	}
+
+	return func(func(T) bool) {}
+}
+
+// All returns an iterator over the heap in pop order.
+// Items are lazily popped from a copy of the heap, so the heap is not modified.
+func (h *Heap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		clone := &Heap[T]{
+			Items:    make([]data.Getter[T], len(h.Items)),
+			LessFunc: h.LessFunc,
+		}
+
+		copy(clone.Items, h.Items)
+
+		for clone.Len() > 0 {
+			if !yield(clone.PopValue()) {
+				return
+			}
+		}
+	}
+}
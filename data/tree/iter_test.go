@@ -0,0 +1,50 @@
+package tree
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/soyart/gsl/data"
+)
+
+func TestAll(t *testing.T) {
+	values := []int{5, 3, 8, 1, 4, 9, 7}
+	expected := []int{1, 3, 4, 5, 7, 8, 9}
+
+	bst := NewBst[int]()
+	if actual := slices.Collect(bst.All()); actual != nil {
+		t.Fatalf("unexpected values from empty tree: %v", actual)
+	}
+
+	for _, x := range values {
+		bst.Insert(x)
+	}
+
+	if actual := slices.Collect(bst.All()); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected bst values: expecting %v, got %v", expected, actual)
+	}
+
+	count := NewBstCount[int](NewBst[int]())
+	for _, x := range values {
+		count.Insert(x)
+	}
+
+	if actual := slices.Collect(count.All()); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected bst count values: expecting %v, got %v", expected, actual)
+	}
+
+	heap := NewHeap[int](data.Descending)
+	for _, x := range values {
+		heap.Push(x)
+	}
+
+	slices.Reverse(expected)
+	if actual := slices.Collect(heap.All()); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected heap values: expecting %v, got %v", expected, actual)
+	}
+
+	if heap.Len() != len(values) {
+		t.Fatalf("heap modified by All")
+	}
+}
@@ -0,0 +1,223 @@
+package tree
+
+import (
+	"iter"
+	"sort"
+)
+
+// Trie is a prefix tree mapping string keys to values of type V.
+// Keys are split into bytes, so keys are iterated in lexicographic
+// (byte-wise, which is also Unicode code point) order.
+//
+// The zero value is an empty Trie ready to use.
+type Trie[V any] struct {
+	root trieNode[V]
+	size int
+}
+
+type trieNode[V any] struct {
+	edges []trieEdge[V] // Sorted by label
+	value V
+	ok    bool
+}
+
+type trieEdge[V any] struct {
+	label byte
+	node  *trieNode[V]
+}
+
+func NewTrie[V any]() *Trie[V] {
+	return new(Trie[V])
+}
+
+// child returns the child of |n| with edge |label|, or nil if there is none
+func (n *trieNode[V]) child(label byte) *trieNode[V] {
+	i, found := n.search(label)
+	if !found {
+		return nil
+	}
+
+	return n.edges[i].node
+}
+
+// search returns the index of edge |label| in n.edges,
+// or where it should be inserted if there is none
+func (n *trieNode[V]) search(label byte) (int, bool) {
+	i := sort.Search(len(n.edges), func(i int) bool {
+		return n.edges[i].label >= label
+	})
+
+	return i, i < len(n.edges) && n.edges[i].label == label
+}
+
+// find returns the node of |key|, or nil if there is none
+func (t *Trie[V]) find(key string) *trieNode[V] {
+	curr := &t.root
+	for i := 0; i < len(key) && curr != nil; i++ {
+		curr = curr.child(key[i])
+	}
+
+	return curr
+}
+
+func (t *Trie[V]) Len() int {
+	return t.size
+}
+
+func (t *Trie[V]) IsEmpty() bool {
+	return t.size == 0
+}
+
+// Insert maps |key| to |value|, and returns true if |key| is new.
+// If |key| is already in the trie, its value is replaced with |value|.
+func (t *Trie[V]) Insert(key string, value V) bool {
+	curr := &t.root
+	for i := 0; i < len(key); i++ {
+		idx, found := curr.search(key[i])
+		if !found {
+			curr.edges = append(curr.edges, trieEdge[V]{})
+			copy(curr.edges[idx+1:], curr.edges[idx:])
+			curr.edges[idx] = trieEdge[V]{label: key[i], node: new(trieNode[V])}
+		}
+
+		curr = curr.edges[idx].node
+	}
+
+	inserted := !curr.ok
+	if inserted {
+		t.size++
+	}
+
+	curr.value, curr.ok = value, true
+
+	return inserted
+}
+
+func (t *Trie[V]) Get(key string) (V, bool) {
+	node := t.find(key)
+	if node == nil || !node.ok {
+		var zero V
+		return zero, false
+	}
+
+	return node.value, true
+}
+
+func (t *Trie[V]) Has(key string) bool {
+	_, ok := t.Get(key)
+	return ok
+}
+
+// Delete removes |key| from the trie, and returns true if |key| was in the trie.
+// Nodes left without keys are removed.
+func (t *Trie[V]) Delete(key string) bool {
+	path := make([]*trieNode[V], 0, len(key)+1)
+
+	curr := &t.root
+	path = append(path, curr)
+
+	for i := 0; i < len(key); i++ {
+		curr = curr.child(key[i])
+		if curr == nil {
+			return false
+		}
+
+		path = append(path, curr)
+	}
+
+	if !curr.ok {
+		return false
+	}
+
+	var zero V
+	curr.value, curr.ok = zero, false
+	t.size--
+
+	// Remove empty nodes from the bottom up
+	for i := len(key); i > 0; i-- {
+		node := path[i]
+		if node.ok || len(node.edges) != 0 {
+			break
+		}
+
+		parent := path[i-1]
+		idx, _ := parent.search(key[i-1])
+		parent.edges = append(parent.edges[:idx], parent.edges[idx+1:]...)
+	}
+
+	return true
+}
+
+// HasPrefix returns true if any key in the trie starts with |prefix|.
+func (t *Trie[V]) HasPrefix(prefix string) bool {
+	node := t.find(prefix)
+	return node != nil && (node.ok || len(node.edges) != 0)
+}
+
+// LongestPrefix returns the longest key in the trie which is a prefix of |s|,
+// e.g. for routing tables. It returns false if no key is a prefix of |s|.
+func (t *Trie[V]) LongestPrefix(s string) (string, V, bool) {
+	var value V
+	length, ok := 0, false
+
+	curr := &t.root
+	for i := 0; curr != nil; i++ {
+		if curr.ok {
+			value, length, ok = curr.value, i, true
+		}
+
+		if i == len(s) {
+			break
+		}
+
+		curr = curr.child(s[i])
+	}
+
+	return s[:length], value, ok
+}
+
+// WithPrefix returns an iterator over keys starting with |prefix| and their values,
+// in lexicographic order of the keys.
+func (t *Trie[V]) WithPrefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		node := t.find(prefix)
+		if node == nil {
+			return
+		}
+
+		trieWalk(node, []byte(prefix), yield)
+	}
+}
+
+// All returns an iterator over all keys and values in lexicographic order of the keys.
+func (t *Trie[V]) All() iter.Seq2[string, V] {
+	return t.WithPrefix("")
+}
+
+// Keys returns an iterator over all keys in lexicographic order.
+func (t *Trie[V]) Keys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for key := range t.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// trieWalk yields keys of the subtree rooted at |node| in preorder,
+// which is lexicographic order because edges are sorted.
+// It returns false if |yield| returns false.
+func trieWalk[V any](node *trieNode[V], key []byte, yield func(string, V) bool) bool {
+	if node.ok && !yield(string(key), node.value) {
+		return false
+	}
+
+	for _, edge := range node.edges {
+		if !trieWalk(edge.node, append(key, edge.label), yield) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package tree
+
+import (
+	"maps"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTrie(t *testing.T) {
+	var trie Trie[int]
+
+	keys := []string{"tea", "ten", "to", "inn", "in", "", "ภาษา", "ภา", "A"}
+	for i, key := range keys {
+		if !trie.Insert(key, i) {
+			t.Fatalf("Insert returned false for new key %q", key)
+		}
+	}
+
+	if trie.Insert("to", 69) {
+		t.Fatalf("Insert returned true for existing key")
+	}
+
+	if trie.Len() != len(keys) {
+		t.Fatalf("unexpected length %d", trie.Len())
+	}
+
+	if v, ok := trie.Get("to"); !ok || v != 69 {
+		t.Fatalf("unexpected value %d for replaced key", v)
+	}
+
+	if trie.Has("te") || trie.Has("teas") || !trie.Has("") {
+		t.Fatalf("unexpected Has result")
+	}
+
+	expected := []string{"", "A", "in", "inn", "tea", "ten", "to", "ภา", "ภาษา"}
+	if actual := slices.Collect(trie.Keys()); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected keys: expecting %q, got %q", expected, actual)
+	}
+
+	if actual := slices.Collect(maps.Keys(maps.Collect(trie.WithPrefix("te")))); len(actual) != 2 {
+		t.Fatalf("unexpected keys with prefix te: %q", actual)
+	}
+
+	type test struct {
+		s        string
+		expected string
+	}
+
+	tests := []test{
+		{s: "innocent", expected: "inn"},
+		{s: "ink", expected: "in"},
+		{s: "tent", expected: "ten"},
+		{s: "t", expected: ""},
+		{s: "ภาษาไทย", expected: "ภาษา"},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if prefix, _, ok := trie.LongestPrefix(test.s); !ok || prefix != test.expected {
+			t.Logf("Expecting %q", test.expected)
+			t.Fatalf("[%d] unexpected longest prefix of %q: %q", i, test.s, prefix)
+		}
+	}
+
+	if !trie.HasPrefix("te") || trie.HasPrefix("x") {
+		t.Fatalf("unexpected HasPrefix result")
+	}
+
+	if !trie.Delete("inn") || trie.Delete("inn") || trie.Delete("i") {
+		t.Fatalf("unexpected Delete result")
+	}
+
+	if !trie.Delete("ภาษา") || trie.HasPrefix("ภาษ") || !trie.Has("ภา") {
+		t.Fatalf("Delete did not prune empty nodes")
+	}
+
+	trie.Delete("")
+	if _, _, ok := trie.LongestPrefix("x"); ok {
+		t.Fatalf("unexpected longest prefix after deleting empty key")
+	}
+}
+
+func TestTrieRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(6, 9))
+	trie := NewTrie[int]()
+	m := make(map[string]int)
+
+	for i := 0; i < 5000; i++ {
+		var b strings.Builder
+		for n := rng.IntN(6); n > 0; n-- {
+			b.WriteByte("abc"[rng.IntN(3)])
+		}
+
+		key := b.String()
+		if rng.IntN(3) == 0 {
+			_, exists := m[key]
+			if trie.Delete(key) != exists {
+				t.Fatalf("unexpected Delete result for %q", key)
+			}
+
+			delete(m, key)
+			continue
+		}
+
+		_, exists := m[key]
+		if trie.Insert(key, i) == exists {
+			t.Fatalf("unexpected Insert result for %q", key)
+		}
+
+		m[key] = i
+	}
+
+	if actual := maps.Collect(trie.All()); !reflect.DeepEqual(m, actual) || trie.Len() != len(m) {
+		t.Fatalf("trie does not match map")
+	}
+
+	if keys := slices.Collect(trie.Keys()); !slices.IsSorted(keys) {
+		t.Fatalf("keys are not sorted: %q", keys)
+	}
+}
@@ -0,0 +1,278 @@
+package gsl
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Levenshtein returns the Levenshtein distance between |a| and |b|,
+// i.e. the minimum number of rune insertions, deletions and substitutions
+// needed to change |a| into |b|.
+func Levenshtein[T ~string](a, b T) int {
+	ra, rb := []rune(string(a)), []rune(string(b))
+
+	// Keep the shorter string in rb, so that rows are short
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// DamerauLevenshtein returns the Damerau-Levenshtein distance between |a| and |b|,
+// which is like Levenshtein, but also counts transposition of 2 adjacent runes
+// as 1 edit, e.g. the distance between "ca" and "abc" is 2.
+//
+// This is the unrestricted distance (Lowrance-Wagner), not the optimal string
+// alignment distance, so substrings may be edited more than once.
+func DamerauLevenshtein[T ~string](a, b T) int {
+	ra, rb := []rune(string(a)), []rune(string(b))
+	inf := len(ra) + len(rb)
+
+	// d[i+1][j+1] is the distance between ra[:i] and rb[:j],
+	// with an extra row and column of inf as sentinels
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+		d[i][0] = inf
+	}
+
+	for j := range d[0] {
+		d[0][j] = inf
+	}
+
+	for i := 0; i <= len(ra); i++ {
+		d[i+1][1] = i
+	}
+
+	for j := 0; j <= len(rb); j++ {
+		d[1][j+1] = j
+	}
+
+	// lastRow[r] is the last row in which ra has rune r
+	lastRow := make(map[rune]int)
+
+	for i := 1; i <= len(ra); i++ {
+		// lastCol is the last column in this row with a match
+		lastCol := 0
+
+		for j := 1; j <= len(rb); j++ {
+			k := lastRow[rb[j-1]]
+			l := lastCol
+
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				lastCol = j
+			}
+
+			d[i+1][j+1] = min(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+				d[k][l]+(i-k-1)+1+(j-l-1),
+			)
+		}
+
+		lastRow[ra[i-1]] = i
+	}
+
+	return d[len(ra)+1][len(rb)+1]
+}
+
+// Jaro returns the Jaro similarity between |a| and |b|, from 0 (no similarity)
+// to 1 (exact match). 2 empty strings are an exact match.
+func Jaro[T ~string](a, b T) float64 {
+	ra, rb := []rune(string(a)), []rune(string(b))
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+
+	var matches int
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+
+		for j := lo; j < hi; j++ {
+			if matchedB[j] || ra[i] != rb[j] {
+				continue
+			}
+
+			matchedA[i], matchedB[j] = true, true
+			matches++
+
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	// Count matched runes that are out of order
+	var transpositions, j int
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+
+		for !matchedB[j] {
+			j++
+		}
+
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions/2))/m) / 3
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity between |a| and |b|,
+// from 0 (no similarity) to 1 (exact match). It is Jaro similarity,
+// boosted for strings sharing a common prefix of up to 4 runes.
+func JaroWinkler[T ~string](a, b T) float64 {
+	const scaling = 0.1
+
+	similarity := Jaro(a, b)
+
+	var prefix int
+	for _, r := range string(a) {
+		if prefix == 4 {
+			break
+		}
+
+		rb, size := utf8.DecodeRuneInString(string(b))
+		if size == 0 || r != rb {
+			break
+		}
+
+		b = b[size:]
+		prefix++
+	}
+
+	return similarity + float64(prefix)*scaling*(1-similarity)
+}
+
+// LongestCommonSubsequence returns the longest sequence of runes found in both |a| and |b|
+// in the same order, but not necessarily contiguous. If there are many such subsequences,
+// only 1 of them is returned.
+func LongestCommonSubsequence[T ~string](a, b T) T {
+	ra, rb := []rune(string(a)), []rune(string(b))
+
+	// lengths[i][j] is the LCS length of ra[i:] and rb[j:]
+	lengths := make([][]int, len(ra)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(rb)+1)
+	}
+
+	for i := len(ra) - 1; i >= 0; i-- {
+		for j := len(rb) - 1; j >= 0; j-- {
+			if ra[i] == rb[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+				continue
+			}
+
+			lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+		}
+	}
+
+	lcs := make([]rune, 0, lengths[0][0])
+	for i, j := 0, 0; i < len(ra) && j < len(rb); {
+		switch {
+		case ra[i] == rb[j]:
+			lcs = append(lcs, ra[i])
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return T(lcs)
+}
+
+// ClosestMatch returns at most |k| of |candidates| closest to |input|,
+// e.g. for "did you mean" suggestions of mistyped commands.
+//
+// Candidates are ranked by DamerauLevenshtein distance, then by JaroWinkler similarity,
+// then by their order in |candidates|. Candidates requiring edits to more than half
+// of their runes (or of |input|'s runes, whichever is longer) are not returned.
+func ClosestMatch[T ~string](candidates []T, input T, k int) []T {
+	if k < 1 {
+		return nil
+	}
+
+	type match struct {
+		candidate  T
+		distance   int
+		similarity float64
+	}
+
+	inputLen := utf8.RuneCountInString(string(input))
+
+	var matches []match
+	for _, candidate := range candidates {
+		distance := DamerauLevenshtein(candidate, input)
+		if distance*2 > max(inputLen, utf8.RuneCountInString(string(candidate))) {
+			continue
+		}
+
+		matches = append(matches, match{
+			candidate:  candidate,
+			distance:   distance,
+			similarity: JaroWinkler(candidate, input),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+
+		return matches[i].similarity > matches[j].similarity
+	})
+
+	var closest []T
+	for i := range matches[:min(k, len(matches))] {
+		closest = append(closest, matches[i].candidate)
+	}
+
+	return closest
+}
@@ -0,0 +1,167 @@
+package gsl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	type test struct {
+		a       string
+		b       string
+		lev     int
+		damerau int
+	}
+
+	tests := []test{
+		{a: "", b: "", lev: 0, damerau: 0},
+		{a: "", b: "abc", lev: 3, damerau: 3},
+		{a: "kitten", b: "sitting", lev: 3, damerau: 3},
+		{a: "flaw", b: "lawn", lev: 2, damerau: 2},
+		{a: "ab", b: "ba", lev: 2, damerau: 1},
+		{a: "ca", b: "abc", lev: 3, damerau: 2},
+		{a: "a cat", b: "an act", lev: 3, damerau: 2},
+		{a: "héllo", b: "hello", lev: 1, damerau: 1},
+		{a: "กขค", b: "กคข", lev: 2, damerau: 1},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		// Both distances are symmetric
+		for _, pair := range [][2]string{{test.a, test.b}, {test.b, test.a}} {
+			if d := Levenshtein(pair[0], pair[1]); d != test.lev {
+				t.Logf("Expecting %d", test.lev)
+				t.Fatalf("[%d] unexpected Levenshtein distance between %q and %q: %d", i, pair[0], pair[1], d)
+			}
+
+			if d := DamerauLevenshtein(pair[0], pair[1]); d != test.damerau {
+				t.Logf("Expecting %d", test.damerau)
+				t.Fatalf("[%d] unexpected Damerau-Levenshtein distance between %q and %q: %d", i, pair[0], pair[1], d)
+			}
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	type test struct {
+		a       string
+		b       string
+		jaro    float64
+		winkler float64
+	}
+
+	tests := []test{
+		{a: "", b: "", jaro: 1, winkler: 1},
+		{a: "abc", b: "", jaro: 0, winkler: 0},
+		{a: "abc", b: "xyz", jaro: 0, winkler: 0},
+		{a: "MARTHA", b: "MARHTA", jaro: 0.944444, winkler: 0.961111},
+		{a: "DWAYNE", b: "DUANE", jaro: 0.822222, winkler: 0.84},
+		{a: "DIXON", b: "DICKSONX", jaro: 0.766667, winkler: 0.813333},
+		{a: "สวัสดี", b: "สวัสดี", jaro: 1, winkler: 1},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if j := Jaro(test.a, test.b); !almostEqual(j, test.jaro, 1e-6) {
+			t.Logf("Expecting %v", test.jaro)
+			t.Fatalf("[%d] unexpected Jaro similarity between %q and %q: %v", i, test.a, test.b, j)
+		}
+
+		if jw := JaroWinkler(test.a, test.b); !almostEqual(jw, test.winkler, 1e-6) {
+			t.Logf("Expecting %v", test.winkler)
+			t.Fatalf("[%d] unexpected Jaro-Winkler similarity between %q and %q: %v", i, test.a, test.b, jw)
+		}
+	}
+}
+
+func TestLongestCommonSubsequence(t *testing.T) {
+	type test struct {
+		a        string
+		b        string
+		expected string
+	}
+
+	tests := []test{
+		{a: "", b: "abc", expected: ""},
+		{a: "AGGTAB", b: "GXTXAYB", expected: "GTAB"},
+		{a: "ABCBDAB", b: "BDCABA", expected: "BDAB"},
+		{a: "ก1ข2ค", b: "กขค", expected: "กขค"},
+		{a: "abc", b: "xyz", expected: ""},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if lcs := LongestCommonSubsequence(test.a, test.b); lcs != test.expected {
+			t.Logf("Expecting %q", test.expected)
+			t.Fatalf("[%d] unexpected LCS of %q and %q: %q", i, test.a, test.b, lcs)
+		}
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	commands := []string{"commit", "checkout", "cherry-pick", "clone", "status", "stash"}
+
+	type test struct {
+		input    string
+		k        int
+		expected []string
+	}
+
+	tests := []test{
+		{input: "comit", k: 3, expected: []string{"commit"}},
+		{input: "stats", k: 3, expected: []string{"status", "stash"}},
+		{input: "stats", k: 1, expected: []string{"status"}},
+		{input: "chekcout", k: 3, expected: []string{"checkout"}},
+		{input: "xyz", k: 3, expected: nil},
+		{input: "commit", k: 0, expected: nil},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if matches := ClosestMatch(commands, test.input, test.k); !reflect.DeepEqual(matches, test.expected) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%d] unexpected matches for %q: %v", i, test.input, matches)
+		}
+	}
+}
+
+const (
+	benchmarkDistanceA = "the quick brown fox jumps over the lazy dog"
+	benchmarkDistanceB = "the quikc borwn fox jumped over a lazy dgo"
+)
+
+func BenchmarkLevenshtein(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Levenshtein(benchmarkDistanceA, benchmarkDistanceB)
+	}
+}
+
+func BenchmarkDamerauLevenshtein(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		DamerauLevenshtein(benchmarkDistanceA, benchmarkDistanceB)
+	}
+}
+
+func BenchmarkJaroWinkler(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		JaroWinkler(benchmarkDistanceA, benchmarkDistanceB)
+	}
+}
+
+func BenchmarkLongestCommonSubsequence(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		LongestCommonSubsequence(benchmarkDistanceA, benchmarkDistanceB)
+	}
+}
+
+func BenchmarkClosestMatch(b *testing.B) {
+	candidates := SplitWords(benchmarkDistanceA + " " + benchmarkDistanceB)
+
+	for i := 0; i < b.N; i++ {
+		ClosestMatch(candidates, "quick", 3)
+	}
+}
@@ -9,9 +9,7 @@ import (
 var ErrNotConvertible = errors.New("types are not convertible")
 
 func fmtError[T any](which string, val interface{}, err error) error {
-	t := ZeroedValue[T]()
-	typeVal, typeTarget := reflect.TypeOf(val).String(), reflect.TypeOf(t).String()
-	return errors.Wrapf(err, "cannot convert %s (of type %s) to type %s", which, typeVal, typeTarget)
+	return errors.Wrapf(err, "cannot convert %s (of type %T) to type %s", which, val, reflect.TypeFor[T]().String())
 }
 
 // CompareInterfaceValues will compares |a| and |b| as type T.
@@ -34,8 +32,9 @@ func CompareInterfaceValues[T comparable](a, b interface{}) (bool, error) {
 	return assertedA == assertedB, nil
 }
 
-// InterfaceTo converts v from interface{} to T.
-// It returns a zeroed T and an error if not convertible.
+// InterfaceTo converts v from interface{} to T with ConvertValue,
+// e.g. int 69 to string "69", or float64 3.0 (from JSON) to int 3.
+// It returns a zeroed T and an error wrapping ErrNotConvertible if not convertible.
 func InterfaceTo[T any](v interface{}) (T, error) {
 	// If we can directly assert the type, then return the asserted value
 	t, ok := v.(T)
@@ -43,16 +42,16 @@ func InterfaceTo[T any](v interface{}) (T, error) {
 		return t, nil
 	}
 
-	// Otherwise use reflect package to convert v to T
-	valueType := reflect.TypeOf(v)
-	targetType := reflect.TypeOf(t)
+	converted, err := ConvertValue(v, reflect.TypeFor[T]())
+	if err != nil {
+		return t, err
+	}
 
-	if valueType.ConvertibleTo(targetType) {
-		valueInTypeT, ok := reflect.ValueOf(v).Convert(targetType).Interface().(T)
-		if ok {
-			return valueInTypeT, nil
-		}
+	// A nil v converts to a nil interface, which cannot be asserted to interface type T
+	if x, ok := converted.Interface().(T); ok {
+		return x, nil
 	}
 
-	return t, errors.Wrapf(ErrNotConvertible, "cannot convert type %s to type %s", valueType.String(), targetType.String())
+	var zero T
+	return zero, nil
 }
@@ -23,7 +23,7 @@ func TestEmptyInterface(t *testing.T) {
 	i := int8(69)
 	v = i
 	testInterfaceTo[float32](t, v, true)
-	testInterfaceTo[string](t, v, true) // Numbers get converted into ASCII char byte
+	testInterfaceTo[string](t, v, true) // Numbers get formatted as decimal strings
 
 	v = int64(2000000)
 	testInterfaceTo[string](t, v, true)
@@ -32,13 +32,24 @@ func TestEmptyInterface(t *testing.T) {
 	v = bar{a: 69, b: false}
 	testInterfaceTo[string](t, v, false)
 
+	// Nil converts to nil interfaces and pointers
+	testInterfaceTo[any](t, nil, true)
+	testInterfaceTo[error](t, nil, true)
+	testInterfaceTo[*bar](t, nil, true)
+
+	if x, err := InterfaceTo[error](nil); x != nil || err != nil {
+		t.Fatalf("unexpected result from nil input: %v, %v", x, err)
+	}
+
 	s := "henlo"
 	testCompareInterfaceValues[string](t, s, fooString, true, false)
 
 	s = string(fooString)
 	testCompareInterfaceValues[foo](t, s, fooString, true, true)
 
-	testCompareInterfaceValues[string](t, float64(512), fooString, false, false)
+	testCompareInterfaceValues[string](t, float64(512), fooString, true, false)
+	testCompareInterfaceValues[int](t, float64(512), "512", true, true)
+	testCompareInterfaceValues[int](t, float64(512.5), 512, false, false)
 	testCompareInterfaceValues[string](t, uint8(69), fooString, true, false)
 	testCompareInterfaceValues[string](t, int(512), fooString, true, false)
 
@@ -0,0 +1,222 @@
+// Package enum provides a registry of allowed values for string-backed
+// and integer-backed enum types, with parsing, formatting and
+// text/JSON/YAML marshalling that rejects unknown values.
+//
+// An Enum is usually a package-level variable, and the enum type's methods
+// delegate to it. Such methods can be generated with cmd/enumgen:
+//
+//	type Color uint8
+//
+//	const (
+//		Red Color = iota + 1
+//		Green
+//	)
+//
+//	var colors = enum.New(map[Color]string{Red: "red", Green: "green"})
+//
+//	func (c Color) String() string                { return colors.String(c) }
+//	func (c Color) IsValid() bool                 { return colors.IsValid(c) }
+//	func (c Color) MarshalText() ([]byte, error)  { return colors.MarshalText(c) }
+//	func (c *Color) UnmarshalText(b []byte) error { return colors.UnmarshalText(c, b) }
+package enum
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/constraints"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknown = errors.New("unknown enum value")
+
+// Underlying is the constraint for enum types
+type Underlying interface {
+	~string | constraints.Integer
+}
+
+// Enum is an immutable registry of the allowed values of enum type E and their names.
+// Values are sorted by their underlying values, so integer enums declared
+// with iota keep their declaration order.
+type Enum[E Underlying] struct {
+	values []E
+	names  []string
+	index  map[E]int
+	byName map[string]int
+}
+
+// New returns an Enum whose allowed values are keys of |names|,
+// named by the values of |names|.
+//
+// New panics if any name is empty or is used by more than 1 value,
+// because enums are registered once at init time.
+func New[E Underlying](names map[E]string) *Enum[E] {
+	e := &Enum[E]{
+		values: make([]E, 0, len(names)),
+		names:  make([]string, len(names)),
+		index:  make(map[E]int, len(names)),
+		byName: make(map[string]int, len(names)),
+	}
+
+	for value := range names {
+		e.values = append(e.values, value)
+	}
+
+	sort.Slice(e.values, func(i, j int) bool {
+		return e.values[i] < e.values[j]
+	})
+
+	for i, value := range e.values {
+		name := names[value]
+		if name == "" {
+			panic("enum: empty name for " + goString(value))
+		}
+
+		if _, dup := e.byName[name]; dup {
+			panic(fmt.Sprintf("enum: duplicate name %q for %T", name, value))
+		}
+
+		e.names[i] = name
+		e.index[value] = i
+		e.byName[name] = i
+	}
+
+	return e
+}
+
+// NewString returns an Enum for string-backed enum type E,
+// whose allowed values are |values|, named by the values themselves.
+func NewString[E ~string](values ...E) *Enum[E] {
+	names := make(map[E]string, len(values))
+	for _, value := range values {
+		names[value] = string(value)
+	}
+
+	return New(names)
+}
+
+// Values returns all allowed values in order.
+func (e *Enum[E]) Values() []E {
+	return append([]E(nil), e.values...)
+}
+
+// Names returns names of all allowed values, in the same order as Values.
+func (e *Enum[E]) Names() []string {
+	return append([]string(nil), e.names...)
+}
+
+func (e *Enum[E]) Len() int {
+	return len(e.values)
+}
+
+func (e *Enum[E]) IsValid(value E) bool {
+	_, ok := e.index[value]
+	return ok
+}
+
+// Name returns the name of |value|, or false if |value| is not allowed.
+func (e *Enum[E]) Name(value E) (string, bool) {
+	i, ok := e.index[value]
+	if !ok {
+		return "", false
+	}
+
+	return e.names[i], true
+}
+
+// String returns the name of |value|, or a Go-syntax representation
+// such as Color(69) or Status("foo") if |value| is not allowed.
+func (e *Enum[E]) String(value E) string {
+	if name, ok := e.Name(value); ok {
+		return name
+	}
+
+	return goString(value)
+}
+
+// Parse returns the value named |s|, or ErrUnknown if there is none.
+func (e *Enum[E]) Parse(s string) (E, error) {
+	i, ok := e.byName[s]
+	if !ok {
+		var zero E
+		return zero, errors.Wrapf(ErrUnknown, "%T %q", zero, s)
+	}
+
+	return e.values[i], nil
+}
+
+// MustParse is like Parse, but panics if |s| is unknown.
+func (e *Enum[E]) MustParse(s string) E {
+	value, err := e.Parse(s)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return value
+}
+
+// MarshalText returns the name of |value|, or ErrUnknown if |value| is not allowed.
+//
+// Because encoding/json uses encoding.TextMarshaler and encoding.TextUnmarshaler,
+// enum types implementing them with MarshalText and UnmarshalText
+// are encoded as JSON strings, and non-string JSON values are rejected.
+func (e *Enum[E]) MarshalText(value E) ([]byte, error) {
+	name, ok := e.Name(value)
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknown, "%s", e.String(value))
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText parses |b| into |dst|. |dst| is not modified if |b| is unknown.
+func (e *Enum[E]) UnmarshalText(dst *E, b []byte) error {
+	value, err := e.Parse(string(b))
+	if err != nil {
+		return err
+	}
+
+	*dst = value
+	return nil
+}
+
+// MarshalYAML returns the name of |value|, or ErrUnknown if |value| is not allowed.
+func (e *Enum[E]) MarshalYAML(value E) (interface{}, error) {
+	b, err := e.MarshalText(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// UnmarshalYAML parses scalar |node| into |dst|.
+//
+// Unlike encoding/json, yaml.v3 decodes non-string scalars such as `3`
+// directly into integer types, bypassing encoding.TextUnmarshaler,
+// so enum types should also implement yaml.Unmarshaler with this method.
+func (e *Enum[E]) UnmarshalYAML(dst *E, node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		var zero E
+		return errors.Wrapf(ErrUnknown, "%T: line %d: expecting scalar", zero, node.Line)
+	}
+
+	return e.UnmarshalText(dst, []byte(node.Value))
+}
+
+// goString formats |value| as its underlying type, e.g. Color(69) or Status("foo"),
+// without calling String methods of E
+func goString[E Underlying](value E) string {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("%T(%q)", value, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("%T(%d)", value, v.Int())
+	}
+
+	return fmt.Sprintf("%T(%d)", value, v.Uint())
+}
@@ -0,0 +1,131 @@
+package enum
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type color int8
+
+const (
+	red color = iota - 1
+	green
+	blue
+)
+
+var colors = New(map[color]string{blue: "blue", red: "red", green: "green"})
+
+func (c color) String() string                { return colors.String(c) }
+func (c color) MarshalText() ([]byte, error)  { return colors.MarshalText(c) }
+func (c *color) UnmarshalText(b []byte) error { return colors.UnmarshalText(c, b) }
+func (c color) MarshalYAML() (interface{}, error) {
+	return colors.MarshalYAML(c)
+}
+
+func (c *color) UnmarshalYAML(node *yaml.Node) error {
+	return colors.UnmarshalYAML(c, node)
+}
+
+type status string
+
+var statuses = NewString[status]("inactive", "active")
+
+func (s status) String() string                { return statuses.String(s) }
+func (s status) MarshalText() ([]byte, error)  { return statuses.MarshalText(s) }
+func (s *status) UnmarshalText(b []byte) error { return statuses.UnmarshalText(s, b) }
+
+func TestEnum(t *testing.T) {
+	if values := colors.Values(); !reflect.DeepEqual(values, []color{red, green, blue}) {
+		t.Fatalf("unexpected values %v", values)
+	}
+
+	if names := statuses.Names(); !reflect.DeepEqual(names, []string{"active", "inactive"}) {
+		t.Fatalf("unexpected names %v", names)
+	}
+
+	if c, err := colors.Parse("blue"); err != nil || c != blue {
+		t.Fatalf("unexpected parse result %v, err %v", c, err)
+	}
+
+	if _, err := colors.Parse("Blue"); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("expecting ErrUnknown, got %v", err)
+	}
+
+	type test struct {
+		actual   string
+		expected string
+	}
+
+	tests := []test{
+		{actual: red.String(), expected: "red"},
+		{actual: color(69).String(), expected: "enum.color(69)"},
+		{actual: color(-2).String(), expected: "enum.color(-2)"},
+		{actual: status("foo").String(), expected: `enum.status("foo")`},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if test.actual != test.expected {
+			t.Logf("Expecting %s", test.expected)
+			t.Fatalf("[%d] unexpected string %s", i, test.actual)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expecting panic for duplicate names")
+			}
+		}()
+
+		New(map[color]string{red: "red", blue: "red"})
+	}()
+}
+
+func TestEnumMarshal(t *testing.T) {
+	type config struct {
+		Color  color  `json:"color" yaml:"color"`
+		Status status `json:"status" yaml:"status"`
+	}
+
+	b, err := json.Marshal(config{Color: blue, Status: "active"})
+	if err != nil || string(b) != `{"color":"blue","status":"active"}` {
+		t.Fatalf("unexpected json %s, err %v", b, err)
+	}
+
+	if _, err := json.Marshal(config{Color: 3, Status: "active"}); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("expecting ErrUnknown when marshaling unknown value, got %v", err)
+	}
+
+	var c config
+	if err := json.Unmarshal(b, &c); err != nil || c.Color != blue || c.Status != "active" {
+		t.Fatalf("unexpected config %+v, err %v", c, err)
+	}
+
+	for _, s := range []string{`{"color":"pink"}`, `{"color":1}`, `{"status":"foo"}`} {
+		if err := json.Unmarshal([]byte(s), &c); err == nil {
+			t.Fatalf("expecting error for %s", s)
+		}
+	}
+
+	y, err := yaml.Marshal(config{Color: red, Status: "inactive"})
+	if err != nil || string(y) != "color: red\nstatus: inactive\n" {
+		t.Fatalf("unexpected yaml %s, err %v", y, err)
+	}
+
+	c = config{}
+	if err := yaml.Unmarshal(y, &c); err != nil || c.Color != red || c.Status != "inactive" {
+		t.Fatalf("unexpected config %+v, err %v", c, err)
+	}
+
+	for _, s := range []string{"color: 1", "color: pink", "color: [red]"} {
+		if err := yaml.Unmarshal([]byte(s), &c); err == nil {
+			t.Fatalf("expecting error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package gsl
+
+import (
+	"iter"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/constraints"
+)
+
+var ErrBadIntervalSet = errors.New("bad interval set string")
+
+// IntervalSet is a set of integers stored as sorted, disjoint closed ranges,
+// e.g. {1, 2, 3, 5, 6} is stored as [1, 3] and [5, 6].
+// Adjacent ranges are always merged, so the ranges are unique for each set.
+//
+// The zero value is an empty set ready to use. IntervalSet is not safe for concurrent use.
+// It is encoded as text (and JSON strings) like "1-3,5-6,8", which can be parsed
+// back with ParseIntervalSet or UnmarshalText.
+type IntervalSet[N constraints.Integer] struct {
+	ranges [][2]N
+}
+
+// NewIntervalSet returns a set of |values|, grouped into ranges with GroupConsecutive.
+// |values| is not modified.
+func NewIntervalSet[N constraints.Integer](values ...N) *IntervalSet[N] {
+	s := new(IntervalSet[N])
+	s.AddValues(values...)
+
+	return s
+}
+
+// IntervalSetOf returns a set of closed |ranges|, which may overlap.
+func IntervalSetOf[N constraints.Integer](ranges ...[2]N) *IntervalSet[N] {
+	s := new(IntervalSet[N])
+	for _, r := range ranges {
+		s.Add(r[0], r[1])
+	}
+
+	return s
+}
+
+// ParseIntervalSet parses |str| in the format of String, e.g. "1-3,5-6,8".
+// Negative numbers are allowed, e.g. "-5--3,0". An empty string is an empty set.
+func ParseIntervalSet[N constraints.Integer](str string) (*IntervalSet[N], error) {
+	s := new(IntervalSet[N])
+	if err := s.UnmarshalText([]byte(str)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Add adds all integers in closed range [from, to]. If |from| > |to|, they are swapped.
+func (s *IntervalSet[N]) Add(from, to N) {
+	if from > to {
+		from, to = to, from
+	}
+
+	ranges := make([][2]N, 0, len(s.ranges)+1)
+	i := 0
+
+	// Ranges before [from, to], which are not adjacent to it
+	for ; i < len(s.ranges) && s.ranges[i][1] < from && s.ranges[i][1]+1 != from; i++ {
+		ranges = append(ranges, s.ranges[i])
+	}
+
+	// Ranges overlapping or adjacent to [from, to] are merged
+	for ; i < len(s.ranges) && (s.ranges[i][0] <= to || s.ranges[i][0]-1 == to); i++ {
+		from = min(from, s.ranges[i][0])
+		to = max(to, s.ranges[i][1])
+	}
+
+	ranges = append(ranges, [2]N{from, to})
+	s.ranges = append(ranges, s.ranges[i:]...)
+}
+
+// AddValues adds |values|, grouping them into ranges with GroupConsecutive.
+// |values| is not modified.
+func (s *IntervalSet[N]) AddValues(values ...N) {
+	if len(values) == 0 {
+		return
+	}
+
+	for _, r := range GroupConsecutive(CopySlice(values)) {
+		s.Add(r[0], r[1])
+	}
+}
+
+// Remove removes all integers in closed range [from, to]. If |from| > |to|, they are swapped.
+func (s *IntervalSet[N]) Remove(from, to N) {
+	if from > to {
+		from, to = to, from
+	}
+
+	ranges := make([][2]N, 0, len(s.ranges)+1)
+	for _, r := range s.ranges {
+		if r[1] < from || r[0] > to {
+			ranges = append(ranges, r)
+			continue
+		}
+
+		if r[0] < from {
+			ranges = append(ranges, [2]N{r[0], from - 1})
+		}
+
+		if r[1] > to {
+			ranges = append(ranges, [2]N{to + 1, r[1]})
+		}
+	}
+
+	s.ranges = ranges
+}
+
+// Contains returns whether |n| is in the set in O(log n) time.
+func (s *IntervalSet[N]) Contains(n N) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i][1] >= n
+	})
+
+	return i < len(s.ranges) && s.ranges[i][0] <= n
+}
+
+// Union returns a new set of integers in either |s| or |other|.
+func (s *IntervalSet[N]) Union(other *IntervalSet[N]) *IntervalSet[N] {
+	union := s.Clone()
+	for _, r := range other.ranges {
+		union.Add(r[0], r[1])
+	}
+
+	return union
+}
+
+// Intersect returns a new set of integers in both |s| and |other|.
+func (s *IntervalSet[N]) Intersect(other *IntervalSet[N]) *IntervalSet[N] {
+	intersection := new(IntervalSet[N])
+
+	for i, j := 0, 0; i < len(s.ranges) && j < len(other.ranges); {
+		a, b := s.ranges[i], other.ranges[j]
+
+		from, to := max(a[0], b[0]), min(a[1], b[1])
+		if from <= to {
+			intersection.ranges = append(intersection.ranges, [2]N{from, to})
+		}
+
+		if a[1] < b[1] {
+			i++
+			continue
+		}
+
+		j++
+	}
+
+	return intersection
+}
+
+// Difference returns a new set of integers in |s| but not in |other|.
+func (s *IntervalSet[N]) Difference(other *IntervalSet[N]) *IntervalSet[N] {
+	difference := s.Clone()
+	for _, r := range other.ranges {
+		difference.Remove(r[0], r[1])
+	}
+
+	return difference
+}
+
+// Complement returns a new set of integers in closed range [lo, hi] that are not in |s|.
+// If |lo| > |hi|, the result is empty.
+func (s *IntervalSet[N]) Complement(lo, hi N) *IntervalSet[N] {
+	complement := new(IntervalSet[N])
+	if lo > hi {
+		return complement
+	}
+
+	curr := lo
+	for _, r := range s.ranges {
+		if r[1] < lo {
+			continue
+		}
+
+		if r[0] > hi {
+			break
+		}
+
+		if r[0] > curr {
+			complement.ranges = append(complement.ranges, [2]N{curr, r[0] - 1})
+		}
+
+		// Check before r[1] + 1, which may overflow
+		if r[1] >= hi {
+			return complement
+		}
+
+		curr = r[1] + 1
+	}
+
+	complement.ranges = append(complement.ranges, [2]N{curr, hi})
+	return complement
+}
+
+// Clone returns a deep copy of |s|.
+func (s *IntervalSet[N]) Clone() *IntervalSet[N] {
+	return &IntervalSet[N]{ranges: CopySlice(s.ranges)}
+}
+
+// Ranges returns a copy of the sorted, disjoint closed ranges.
+func (s *IntervalSet[N]) Ranges() [][2]N {
+	return CopySlice(s.ranges)
+}
+
+func (s *IntervalSet[N]) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+// Equal returns whether |s| and |other| have the same integers.
+func (s *IntervalSet[N]) Equal(other *IntervalSet[N]) bool {
+	if len(s.ranges) != len(other.ranges) {
+		return false
+	}
+
+	for i := range s.ranges {
+		if s.ranges[i] != other.ranges[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// All returns an iterator over integers in the set in ascending order.
+func (s *IntervalSet[N]) All() iter.Seq[N] {
+	return func(yield func(N) bool) {
+		for _, r := range s.ranges {
+			for n := r[0]; ; n++ {
+				if !yield(n) {
+					return
+				}
+
+				// Check before n++, which may overflow
+				if n == r[1] {
+					break
+				}
+			}
+		}
+	}
+}
+
+// String returns the compact form of |s|, e.g. "1-3,5-6,8".
+func (s *IntervalSet[N]) String() string {
+	text, _ := s.MarshalText()
+	return string(text)
+}
+
+func (s *IntervalSet[N]) MarshalText() ([]byte, error) {
+	var b []byte
+	for i, r := range s.ranges {
+		if i != 0 {
+			b = append(b, ',')
+		}
+
+		b = appendInteger(b, r[0])
+		if r[0] != r[1] {
+			b = append(b, '-')
+			b = appendInteger(b, r[1])
+		}
+	}
+
+	return b, nil
+}
+
+// UnmarshalText replaces |s| with the set parsed from |text|. Ranges in |text|
+// may be unsorted or overlapping, e.g. "5,1-3,2-4" is parsed as "1-5".
+func (s *IntervalSet[N]) UnmarshalText(text []byte) error {
+	parsed := new(IntervalSet[N])
+
+	str := strings.TrimSpace(string(text))
+	if str == "" {
+		*s = *parsed
+		return nil
+	}
+
+	for _, part := range strings.Split(str, ",") {
+		part = strings.TrimSpace(part)
+
+		// The separator is the first '-' that is not a sign
+		sep := -1
+		if len(part) > 1 {
+			if i := strings.IndexByte(part[1:], '-'); i != -1 {
+				sep = i + 1
+			}
+		}
+
+		fromStr, toStr := part, part
+		if sep != -1 {
+			fromStr, toStr = part[:sep], part[sep+1:]
+		}
+
+		from, err := parseInteger[N](strings.TrimSpace(fromStr))
+		if err != nil {
+			return errors.Wrapf(ErrBadIntervalSet, "bad range %q: %s", part, err.Error())
+		}
+
+		to, err := parseInteger[N](strings.TrimSpace(toStr))
+		if err != nil {
+			return errors.Wrapf(ErrBadIntervalSet, "bad range %q: %s", part, err.Error())
+		}
+
+		if from > to {
+			return errors.Wrapf(ErrBadIntervalSet, "bad range %q: start is greater than end", part)
+		}
+
+		parsed.Add(from, to)
+	}
+
+	*s = *parsed
+	return nil
+}
+
+func isSigned[N constraints.Integer]() bool {
+	var zero N
+	return zero-1 < zero
+}
+
+func appendInteger[N constraints.Integer](b []byte, n N) []byte {
+	if isSigned[N]() {
+		return strconv.AppendInt(b, int64(n), 10)
+	}
+
+	return strconv.AppendUint(b, uint64(n), 10)
+}
+
+func parseInteger[N constraints.Integer](s string) (N, error) {
+	if isSigned[N]() {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || int64(N(n)) != n {
+			return 0, errors.Errorf("bad integer %q", s)
+		}
+
+		return N(n), nil
+	}
+
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || uint64(N(n)) != n {
+		return 0, errors.Errorf("bad integer %q", s)
+	}
+
+	return N(n), nil
+}
@@ -0,0 +1,170 @@
+package gsl
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestIntervalSetAddRemove(t *testing.T) {
+	values := []int{10, 2, 1, 3, 6, 5, 8, 9, 3}
+	s := NewIntervalSet(values...)
+
+	if values[0] != 10 {
+		t.Fatalf("NewIntervalSet modified its input")
+	}
+
+	expected := [][2]int{{1, 3}, {5, 6}, {8, 10}}
+	if !reflect.DeepEqual(expected, s.Ranges()) {
+		t.Fatalf("unexpected ranges -- expecting %v, got %v", expected, s.Ranges())
+	}
+
+	type test struct {
+		add      bool
+		from     int
+		to       int
+		expected string
+	}
+
+	tests := []test{
+		{add: true, from: 4, to: 4, expected: "1-6,8-10"},
+		{add: true, from: 12, to: 11, expected: "1-6,8-12"},
+		{add: true, from: -2, to: -1, expected: "-2--1,1-6,8-12"},
+		{add: true, from: 0, to: 7, expected: "-2-12"},
+		{add: false, from: 3, to: 5, expected: "-2-2,6-12"},
+		{add: false, from: 12, to: 20, expected: "-2-2,6-11"},
+		{add: false, from: -10, to: -2, expected: "-1-2,6-11"},
+		{add: false, from: 2, to: 6, expected: "-1-1,7-11"},
+		{add: false, from: -5, to: 50, expected: ""},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if test.add {
+			s.Add(test.from, test.to)
+		} else {
+			s.Remove(test.from, test.to)
+		}
+
+		if actual := s.String(); actual != test.expected {
+			t.Logf("Expecting %s", test.expected)
+			t.Fatalf("[%d] unexpected set %s", i, actual)
+		}
+	}
+
+	if !s.IsEmpty() {
+		t.Fatalf("expecting empty set")
+	}
+
+	if NewIntervalSet[int]().String() != "" {
+		t.Fatalf("expecting empty set from no values")
+	}
+}
+
+func TestIntervalSetContains(t *testing.T) {
+	s := IntervalSetOf([2]uint8{1, 3}, [2]uint8{250, 255}, [2]uint8{7, 7})
+
+	for n := 0; n <= math.MaxUint8; n++ {
+		expected := (n >= 1 && n <= 3) || n == 7 || n >= 250
+		if s.Contains(uint8(n)) != expected {
+			t.Fatalf("unexpected Contains(%d) -- expecting %v", n, expected)
+		}
+	}
+
+	all := slices.Collect(s.All())
+	expected := []uint8{1, 2, 3, 7, 250, 251, 252, 253, 254, 255}
+	if !reflect.DeepEqual(expected, all) {
+		t.Fatalf("unexpected values -- expecting %v, got %v", expected, all)
+	}
+
+	// Ranges at type bounds must not overflow
+	s.Add(0, 0)
+	s.Add(4, 6)
+	if s.String() != "0-7,250-255" {
+		t.Fatalf("unexpected set %s", s.String())
+	}
+
+	if c := s.Complement(0, 255); c.String() != "8-249" {
+		t.Fatalf("unexpected complement %s", c.String())
+	}
+}
+
+func TestIntervalSetOperations(t *testing.T) {
+	a := IntervalSetOf([2]int{1, 5}, [2]int{10, 15}, [2]int{20, 20})
+	b := IntervalSetOf([2]int{4, 11}, [2]int{14, 22})
+
+	type test struct {
+		name     string
+		actual   *IntervalSet[int]
+		expected string
+	}
+
+	tests := []test{
+		{name: "union", actual: a.Union(b), expected: "1-22"},
+		{name: "intersect", actual: a.Intersect(b), expected: "4-5,10-11,14-15,20"},
+		{name: "difference", actual: a.Difference(b), expected: "1-3,12-13"},
+		{name: "difference reversed", actual: b.Difference(a), expected: "6-9,16-19,21-22"},
+		{name: "complement", actual: a.Complement(0, 12), expected: "0,6-9"},
+		{name: "complement bounded", actual: a.Complement(3, 30), expected: "6-9,16-19,21-30"},
+		{name: "complement empty", actual: a.Complement(10, 1), expected: ""},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if actual := test.actual.String(); actual != test.expected {
+			t.Logf("Expecting %s", test.expected)
+			t.Fatalf("[%s] unexpected set %s", test.name, actual)
+		}
+	}
+
+	if a.String() != "1-5,10-15,20" || b.String() != "4-11,14-22" {
+		t.Fatalf("operations modified their operands")
+	}
+}
+
+func TestIntervalSetEncoding(t *testing.T) {
+	s, err := ParseIntervalSet[int64](" 8-10, 1-3 ,5-6,2,-7--5 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "-7--5,1-3,5-6,8-10"
+	if s.String() != expected {
+		t.Fatalf("unexpected parsed set -- expecting %s, got %s", expected, s.String())
+	}
+
+	type config struct {
+		Ports *IntervalSet[uint16] `json:"ports"`
+	}
+
+	var c config
+	if err := json.Unmarshal([]byte(`{"ports":"80,443,8000-8080"}`), &c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !c.Ports.Contains(8080) || c.Ports.Contains(8081) {
+		t.Fatalf("unexpected decoded set %s", c.Ports)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(b) != `{"ports":"80,443,8000-8080"}` {
+		t.Fatalf("unexpected json %s", b)
+	}
+
+	for _, bad := range []string{"1-", "a", "3-1", "1-2-3", "70000", ",", "-1"} {
+		_, err := ParseIntervalSet[uint16](bad)
+		if !errors.Is(err, ErrBadIntervalSet) {
+			t.Fatalf("expecting ErrBadIntervalSet for %q, got %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// Package iters provides lazy iterator adapters over Go 1.23 iter.Seq and iter.Seq2.
+//
+// Unlike gsl.Map or gsl.FilterSlice, the adapters here do not allocate whole slices:
+// each element is computed only when the consumer asks for it, so pipelines
+// can be stopped early with Take or break.
+//
+// Data structures in data/list and data/tree implement data.Iterable[T],
+// so their All() iterators can be used as sources, e.g.
+//
+//	evens := iters.Collect(iters.Filter(stack.All(), isEven))
+package iters
+
+import (
+	"iter"
+)
+
+// Map returns an iterator that yields f(x) for each x in |seq|.
+func Map[T any, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for x := range seq {
+			if !yield(f(x)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator that yields elements of |seq| that satisfy |f|.
+func Filter[T any](seq iter.Seq[T], f func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := range seq {
+			if f(x) && !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns an iterator that yields at most the first |n| elements of |seq|.
+// |seq| is not pulled beyond the |n|-th element.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+
+		i := 0
+		for x := range seq {
+			if !yield(x) {
+				return
+			}
+
+			i++
+			if i >= n {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns an iterator that yields elements of |seq| after the first |n|.
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for x := range seq {
+			if i < n {
+				i++
+				continue
+			}
+
+			if !yield(x) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk returns an iterator that yields consecutive chunks of |size| elements of |seq|.
+// The last chunk may be shorter. Each chunk is a new slice, so it may be retained.
+// Chunk panics if |size| is less than 1, like slices.Chunk.
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("cannot be less than 1")
+	}
+
+	return func(yield func([]T) bool) {
+		var chunk []T
+		for x := range seq {
+			if chunk == nil {
+				chunk = make([]T, 0, size)
+			}
+
+			chunk = append(chunk, x)
+			if len(chunk) < size {
+				continue
+			}
+
+			if !yield(chunk) {
+				return
+			}
+
+			chunk = nil
+		}
+
+		if len(chunk) != 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window returns an iterator that yields sliding windows of |size| elements of |seq|,
+// e.g. Window(1, 2, 3, 4) with size 2 yields [1 2], [2 3] and [3 4].
+// Nothing is yielded if |seq| has fewer than |size| elements.
+// Each window is a new slice, so it may be retained.
+// Window panics if |size| is less than 1.
+func Window[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size < 1 {
+		panic("cannot be less than 1")
+	}
+
+	return func(yield func([]T) bool) {
+		window := make([]T, 0, size)
+		for x := range seq {
+			if len(window) == size {
+				window = window[1:]
+			}
+
+			window = append(window, x)
+			if len(window) < size {
+				continue
+			}
+
+			out := make([]T, size)
+			copy(out, window)
+
+			if !yield(out) {
+				return
+			}
+		}
+	}
+}
+
+// Zip returns an iterator that yields pairs of elements from |a| and |b|,
+// and stops when either is exhausted.
+func Zip[T any, U any](a iter.Seq[T], b iter.Seq[U]) iter.Seq2[T, U] {
+	return func(yield func(T, U) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+
+		for x := range a {
+			y, ok := next()
+			if !ok {
+				return
+			}
+
+			if !yield(x, y) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator that yields elements of |seq| with their indexes.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for x := range seq {
+			if !yield(i, x) {
+				return
+			}
+
+			i++
+		}
+	}
+}
+
+// FlatMap returns an iterator that yields all elements of f(x) for each x in |seq|.
+func FlatMap[T any, U any](seq iter.Seq[T], f func(T) iter.Seq[U]) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for x := range seq {
+			for y := range f(x) {
+				if !yield(y) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Reduce folds |seq| into a value, starting with |initial|.
+func Reduce[T any, A any](seq iter.Seq[T], initial A, f func(A, T) A) A {
+	acc := initial
+	for x := range seq {
+		acc = f(acc, x)
+	}
+
+	return acc
+}
+
+// Collect consumes |seq| and returns its elements as a slice.
+// It returns nil if |seq| yields nothing.
+func Collect[T any](seq iter.Seq[T]) []T {
+	var result []T
+	for x := range seq {
+		result = append(result, x)
+	}
+
+	return result
+}
+
+// Keys returns an iterator over the first elements of pairs in |seq|.
+func Keys[K any, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the second elements of pairs in |seq|.
+func Values[K any, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package iters
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// counting returns an iterator over 0..n-1, and records how many elements were pulled
+func counting(n int, pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			*pulled++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestMapFilter(t *testing.T) {
+	seq := slices.Values([]int{1, 2, 3, 4, 5, 6})
+	evens := Filter(seq, func(x int) bool { return x%2 == 0 })
+	strs := Map(evens, strconv.Itoa)
+
+	expected := []string{"2", "4", "6"}
+	if actual := Collect(strs); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected result: expecting %v, got %v", expected, actual)
+	}
+}
+
+func TestTakeSkip(t *testing.T) {
+	type test struct {
+		n        int
+		take     int
+		skip     int
+		expected []int
+		pulled   int
+	}
+
+	tests := []test{
+		{n: 10, take: 3, skip: 0, expected: []int{0, 1, 2}, pulled: 3},
+		{n: 10, take: 3, skip: 2, expected: []int{2, 3, 4}, pulled: 5},
+		{n: 3, take: 5, skip: 1, expected: []int{1, 2}, pulled: 3},
+		{n: 3, take: 0, skip: 0, expected: nil, pulled: 0},
+		{n: 3, take: 2, skip: 5, expected: nil, pulled: 3},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		var pulled int
+		actual := Collect(Take(Skip(counting(test.n, &pulled), test.skip), test.take))
+
+		if !reflect.DeepEqual(test.expected, actual) {
+			t.Fatalf("[%d] unexpected result: expecting %v, got %v", i, test.expected, actual)
+		}
+
+		if pulled != test.pulled {
+			t.Logf("Expecting %d elements pulled", test.pulled)
+			t.Fatalf("[%d] unexpected pulled count %d", i, pulled)
+		}
+	}
+}
+
+func TestChunkWindow(t *testing.T) {
+	seq := slices.Values([]int{1, 2, 3, 4, 5})
+
+	chunks := Collect(Chunk(seq, 2))
+	expectedChunks := [][]int{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(expectedChunks, chunks) {
+		t.Fatalf("unexpected chunks: expecting %v, got %v", expectedChunks, chunks)
+	}
+
+	windows := Collect(Window(seq, 3))
+	expectedWindows := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+	if !reflect.DeepEqual(expectedWindows, windows) {
+		t.Fatalf("unexpected windows: expecting %v, got %v", expectedWindows, windows)
+	}
+
+	if windows := Collect(Window(seq, 6)); windows != nil {
+		t.Fatalf("unexpected windows from short seq: %v", windows)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expecting panic from chunk size 0")
+		}
+	}()
+
+	Chunk(seq, 0)
+}
+
+func TestZipEnumerate(t *testing.T) {
+	var pulled int
+	names := slices.Values([]string{"a", "b", "c"})
+
+	var keys []string
+	var values []int
+	for k, v := range Zip(names, counting(10, &pulled)) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) || !reflect.DeepEqual(values, []int{0, 1, 2}) {
+		t.Fatalf("unexpected zip result: %v %v", keys, values)
+	}
+
+	if pulled > 4 {
+		t.Fatalf("zip pulled too many elements: %d", pulled)
+	}
+
+	indexes := Collect(Keys(Enumerate(names)))
+	if !reflect.DeepEqual(indexes, []int{0, 1, 2}) {
+		t.Fatalf("unexpected indexes: %v", indexes)
+	}
+
+	elems := Collect(Values(Enumerate(names)))
+	if !reflect.DeepEqual(elems, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected elements: %v", elems)
+	}
+}
+
+func TestFlatMapReduce(t *testing.T) {
+	seq := slices.Values([]int{1, 2, 3})
+	repeated := FlatMap(seq, func(x int) iter.Seq[int] {
+		return Take(func(yield func(int) bool) {
+			for yield(x) {
+			}
+		}, x)
+	})
+
+	expected := []int{1, 2, 2, 3, 3, 3}
+	if actual := Collect(repeated); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("unexpected flat map result: expecting %v, got %v", expected, actual)
+	}
+
+	sum := Reduce(repeated, 0, func(acc, x int) int { return acc + x })
+	if sum != 14 {
+		t.Fatalf("unexpected sum: expecting 14, got %d", sum)
+	}
+
+	// Early break from FlatMap
+	var pulled int
+	first := Collect(Take(FlatMap(counting(100, &pulled), func(x int) iter.Seq[int] {
+		return slices.Values([]int{x, x})
+	}), 3))
+
+	if !reflect.DeepEqual(first, []int{0, 0, 1}) {
+		t.Fatalf("unexpected result: %v", first)
+	}
+
+	if pulled != 2 {
+		t.Fatalf("unexpected pulled count: expecting 2, got %d", pulled)
+	}
+}
@@ -1,5 +1,22 @@
 package gsl
 
+import (
+	"bytes"
+	"container/list"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/constraints"
+)
+
+var ErrDuplicateMapValue = errors.New("duplicate map value")
+
 // SliceFromMapValues collects map[K]V |m| into []V.
 // If |m| is mil, SliceFromMapValues returns nil
 func SliceFromMapValues[K comparable, V any](m map[K]V) []V {
@@ -117,3 +134,407 @@ func SlicesFromMapIf[K comparable, V any](m map[K]V, f func(K, V) bool) ([]K, []
 
 	return keys, vals
 }
+
+// SortedKeys returns keys of |m| in ascending order.
+// If |m| is nil, SortedKeys returns nil.
+func SortedKeys[K constraints.Ordered, V any](m map[K]V) []K {
+	keys := SliceFromMapKeys(m)
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
+
+// SortedKeysFunc returns keys of |m| sorted with |cmp|, which returns
+// a negative number if a < b, a positive number if a > b, and 0 if a == b.
+func SortedKeysFunc[K comparable, V any](m map[K]V, cmp func(a, b K) int) []K {
+	keys := SliceFromMapKeys(m)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return cmp(keys[i], keys[j]) < 0
+	})
+
+	return keys
+}
+
+// SortedEntries returns key-value pairs of |m| sorted with |cmp|, e.g. by values:
+//
+//	SortedEntries(m, func(a, b Pair[string, int]) int { return a.Second - b.Second })
+//
+// If |m| is nil, SortedEntries returns nil.
+func SortedEntries[K comparable, V any](m map[K]V, cmp func(a, b Pair[K, V]) int) []Pair[K, V] {
+	if m == nil {
+		return nil
+	}
+
+	entries := make([]Pair[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Pair[K, V]{First: k, Second: v})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return cmp(entries[i], entries[j]) < 0
+	})
+
+	return entries
+}
+
+// MergeMaps merges |maps| into a new map. If a key exists in more than one map,
+// |resolve| is called with the key, the value merged so far and the new value,
+// and its result is kept. If |resolve| is nil, the value from the last map wins.
+func MergeMaps[K comparable, V any](resolve func(key K, existing, incoming V) V, maps ...map[K]V) map[K]V {
+	var l int
+	for i := range maps {
+		l += len(maps[i])
+	}
+
+	merged := make(map[K]V, l)
+	for i := range maps {
+		for k, v := range maps[i] {
+			existing, ok := merged[k]
+			if ok && resolve != nil {
+				v = resolve(k, existing, v)
+			}
+
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// InvertMap returns a new map with keys and values of |m| swapped.
+// If values of |m| are not unique, InvertMap returns ErrDuplicateMapValue,
+// because the key kept would depend on map iteration order.
+func InvertMap[K comparable, V comparable](m map[K]V) (map[V]K, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	inverted := make(map[V]K, len(m))
+	for k, v := range m {
+		if _, ok := inverted[v]; ok {
+			return nil, errors.Wrapf(ErrDuplicateMapValue, "value %v", v)
+		}
+
+		inverted[v] = k
+	}
+
+	return inverted, nil
+}
+
+// MapValues returns a new map with the same keys as |m|, and values mapped with |f|.
+func MapValues[K comparable, V any, U any](m map[K]V, f func(K, V) U) map[K]U {
+	if m == nil {
+		return nil
+	}
+
+	mapped := make(map[K]U, len(m))
+	for k, v := range m {
+		mapped[k] = f(k, v)
+	}
+
+	return mapped
+}
+
+// FilterMap returns a new map with entries of |m| that satisfy |f|.
+func FilterMap[K comparable, V any](m map[K]V, f func(K, V) bool) map[K]V {
+	if m == nil {
+		return nil
+	}
+
+	filtered := make(map[K]V)
+	for k, v := range m {
+		if f(k, v) {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
+
+// MapDiff reports keys added, removed and changed between two maps, each in ascending order.
+type MapDiff[K constraints.Ordered] struct {
+	Added   []K
+	Removed []K
+	Changed []K
+}
+
+// IsEmpty returns whether the maps compared are equal.
+func (d MapDiff[K]) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares |from| to |to|: keys only in |to| are added,
+// keys only in |from| are removed, and keys with different values are changed.
+func Diff[K constraints.Ordered, V comparable](from, to map[K]V) MapDiff[K] {
+	return DiffFunc(from, to, func(a, b V) bool { return a == b })
+}
+
+// DiffFunc is like Diff, but compares values with |equal|, e.g. reflect.DeepEqual.
+func DiffFunc[K constraints.Ordered, V any](from, to map[K]V, equal func(a, b V) bool) MapDiff[K] {
+	var diff MapDiff[K]
+
+	for k, v := range from {
+		vTo, ok := to[k]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, k)
+		case !equal(v, vTo):
+			diff.Changed = append(diff.Changed, k)
+		}
+	}
+
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			diff.Added = append(diff.Added, k)
+		}
+	}
+
+	for _, keys := range [][]K{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i] < keys[j]
+		})
+	}
+
+	return diff
+}
+
+// OrderedMap is a map that remembers insertion order of its keys.
+// Setting an existing key updates its value without changing its position.
+//
+// The zero value is an empty map ready to use. OrderedMap is not safe for concurrent use.
+// OrderedMap is encoded to and decoded from JSON objects in order,
+// with keys encoded like encoding/json map keys (strings, integers or encoding.TextMarshaler).
+type OrderedMap[K comparable, V any] struct {
+	entries *list.List
+	index   map[K]*list.Element
+}
+
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{}
+}
+
+func (m *OrderedMap[K, V]) init() {
+	if m.entries == nil {
+		m.entries = list.New()
+		m.index = make(map[K]*list.Element)
+	}
+}
+
+// Set sets value of |key| to |value|, appending |key| if it is new.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	m.init()
+
+	if elem, ok := m.index[key]; ok {
+		elem.Value = Pair[K, V]{First: key, Second: value}
+		return
+	}
+
+	m.index[key] = m.entries.PushBack(Pair[K, V]{First: key, Second: value})
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	elem, ok := m.index[key]
+	if !ok {
+		return ZeroedValue[V](), false
+	}
+
+	return elem.Value.(Pair[K, V]).Second, true
+}
+
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.index[key]
+	return ok
+}
+
+// Delete removes |key|, and returns whether it was in the map.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	elem, ok := m.index[key]
+	if !ok {
+		return false
+	}
+
+	m.entries.Remove(elem)
+	delete(m.index, key)
+
+	return true
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.index)
+}
+
+// All returns an iterator over entries in insertion order.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.entries == nil {
+			return
+		}
+
+		for elem := m.entries.Front(); elem != nil; elem = elem.Next() {
+			entry := elem.Value.(Pair[K, V])
+			if !yield(entry.First, entry.Second) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for k := range m.All() {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// Values returns values in insertion order.
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Len())
+	for _, v := range m.All() {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	i := 0
+	for k, v := range m.All() {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+
+		i++
+
+		keyText, err := marshalMapKey(k)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := json.Marshal(keyText)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal value of key %s", keyText)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON replaces entries of |m| with those of JSON object |b| in order.
+// Duplicate keys in |b| keep their first position, with the last value.
+func (m *OrderedMap[K, V]) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return errors.Errorf("cannot unmarshal %v into OrderedMap", token)
+	}
+
+	*m = OrderedMap[K, V]{}
+	m.init()
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, err := unmarshalMapKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal value of key %s", token)
+		}
+
+		m.Set(key, value)
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// marshalMapKey formats |k| like encoding/json does for map keys
+func marshalMapKey[K comparable](k K) (string, error) {
+	v := reflect.ValueOf(k)
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+
+	if tm, ok := any(k).(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+
+	return "", fmt.Errorf("unsupported OrderedMap key type %T", k)
+}
+
+// unmarshalMapKey parses |s| like encoding/json does for map keys
+func unmarshalMapKey[K comparable](s string) (K, error) {
+	var k K
+
+	if tu, ok := any(&k).(encoding.TextUnmarshaler); ok {
+		return k, tu.UnmarshalText([]byte(s))
+	}
+
+	v := reflect.ValueOf(&k).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return k, errors.Wrapf(err, "bad OrderedMap key %q", s)
+		}
+
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return k, errors.Wrapf(err, "bad OrderedMap key %q", s)
+		}
+
+		v.SetUint(n)
+
+	default:
+		return k, fmt.Errorf("unsupported OrderedMap key type %T", k)
+	}
+
+	return k, nil
+}
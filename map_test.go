@@ -1,8 +1,12 @@
 package gsl
 
 import (
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
 )
 
 func TestSliceFromMap(t *testing.T) {
@@ -123,3 +127,174 @@ func TestSliceFromMapIf(t *testing.T) {
 		}
 	}
 }
+
+func TestSortedKeysEntries(t *testing.T) {
+	m := map[string]int{"c": 1, "a": 3, "b": 2}
+
+	keys := SortedKeys(m)
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected sorted keys %v", keys)
+	}
+
+	desc := SortedKeysFunc(m, func(a, b string) int { return strings.Compare(b, a) })
+	if !reflect.DeepEqual(desc, []string{"c", "b", "a"}) {
+		t.Fatalf("unexpected sorted keys %v", desc)
+	}
+
+	entries := SortedEntries(m, func(a, b Pair[string, int]) int { return a.Second - b.Second })
+	expected := []Pair[string, int]{{"c", 1}, {"b", 2}, {"a", 3}}
+	if !reflect.DeepEqual(expected, entries) {
+		t.Fatalf("unexpected sorted entries %v", entries)
+	}
+
+	if SortedKeys[string, int](nil) != nil {
+		t.Fatalf("expecting nil keys from nil map")
+	}
+}
+
+func TestMapAlgorithms(t *testing.T) {
+	m0 := map[string]int{"a": 1, "b": 2}
+	m1 := map[string]int{"b": 3, "c": 4}
+
+	merged := MergeMaps(nil, m0, m1)
+	if !reflect.DeepEqual(merged, map[string]int{"a": 1, "b": 3, "c": 4}) {
+		t.Fatalf("unexpected merged map %v", merged)
+	}
+
+	summed := MergeMaps(func(_ string, existing, incoming int) int { return existing + incoming }, m0, m1, m1)
+	if !reflect.DeepEqual(summed, map[string]int{"a": 1, "b": 8, "c": 8}) {
+		t.Fatalf("unexpected merged map %v", summed)
+	}
+
+	inverted, err := InvertMap(m0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(inverted, map[int]string{1: "a", 2: "b"}) {
+		t.Fatalf("unexpected inverted map %v", inverted)
+	}
+
+	if _, err := InvertMap(summed); !errors.Is(err, ErrDuplicateMapValue) {
+		t.Fatalf("expecting ErrDuplicateMapValue, got %v", err)
+	}
+
+	doubled := MapValues(m0, func(_ string, v int) string { return strings.Repeat("x", v) })
+	if !reflect.DeepEqual(doubled, map[string]string{"a": "x", "b": "xx"}) {
+		t.Fatalf("unexpected mapped values %v", doubled)
+	}
+
+	filtered := FilterMap(merged, func(k string, v int) bool { return v > 1 })
+	if !reflect.DeepEqual(filtered, map[string]int{"b": 3, "c": 4}) {
+		t.Fatalf("unexpected filtered map %v", filtered)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+	to := map[string]int{"a": 1, "b": 20, "d": 40, "e": 5, "f": 6}
+
+	diff := Diff(from, to)
+	expected := MapDiff[string]{
+		Added:   []string{"e", "f"},
+		Removed: []string{"c"},
+		Changed: []string{"b", "d"},
+	}
+
+	if !reflect.DeepEqual(expected, diff) {
+		t.Logf("Expecting %+v", expected)
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+
+	if !Diff(from, from).IsEmpty() {
+		t.Fatalf("expecting empty diff")
+	}
+
+	slices0 := map[int][]int{1: {1}, 2: {2}}
+	slices1 := map[int][]int{1: {1}, 2: {3}}
+	diffSlices := DiffFunc(slices0, slices1, func(a, b []int) bool { return reflect.DeepEqual(a, b) })
+
+	if !reflect.DeepEqual(diffSlices.Changed, []int{2}) || diffSlices.Added != nil || diffSlices.Removed != nil {
+		t.Fatalf("unexpected diff %+v", diffSlices)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	var m OrderedMap[string, int]
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+
+	if !reflect.DeepEqual(m.Keys(), []string{"z", "a", "m"}) || !reflect.DeepEqual(m.Values(), []int{4, 2, 3}) {
+		t.Fatalf("unexpected entries %v %v", m.Keys(), m.Values())
+	}
+
+	if v, ok := m.Get("z"); !ok || v != 4 {
+		t.Fatalf("unexpected value %d for z", v)
+	}
+
+	if !m.Delete("a") || m.Delete("a") || m.Has("a") || m.Len() != 2 {
+		t.Fatalf("unexpected delete result")
+	}
+
+	m.Set("a", 5)
+
+	b, err := json.Marshal(&m)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	expected := `{"z":4,"m":3,"a":5}`
+	if string(b) != expected {
+		t.Fatalf("unexpected json -- expecting %s, got %s", expected, b)
+	}
+
+	// Non-addressable values, e.g. map values and interfaces holding values
+	for _, v := range []interface{}{m, map[string]OrderedMap[string, int]{"m": m}["m"], map[string]interface{}{"m": m}} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("unexpected marshal error: %v", err)
+		}
+
+		if !strings.Contains(string(b), expected) {
+			t.Fatalf("unexpected json of value -- expecting %s, got %s", expected, b)
+		}
+	}
+
+	var decoded OrderedMap[string, int]
+	if err := json.Unmarshal([]byte(`{"b": 1, "a": 2, "c": 3, "b": 4}`), &decoded); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Keys(), []string{"b", "a", "c"}) || !reflect.DeepEqual(decoded.Values(), []int{4, 2, 3}) {
+		t.Fatalf("unexpected decoded entries %v %v", decoded.Keys(), decoded.Values())
+	}
+
+	// Nested values and integer keys
+	type config struct {
+		Items *OrderedMap[uint16, []string] `json:"items"`
+	}
+
+	var c config
+	if err := json.Unmarshal([]byte(`{"items": {"3": ["x"], "1": [], "2": ["y", "z"]}}`), &c); err != nil {
+		t.Fatalf("unexpected unmarshal error: %v", err)
+	}
+
+	if !reflect.DeepEqual(c.Items.Keys(), []uint16{3, 1, 2}) {
+		t.Fatalf("unexpected decoded keys %v", c.Items.Keys())
+	}
+
+	b, err = json.Marshal(c)
+	if err != nil {
+		t.Fatalf("unexpected marshal error: %v", err)
+	}
+
+	if expected := `{"items":{"3":["x"],"1":[],"2":["y","z"]}}`; string(b) != expected {
+		t.Fatalf("unexpected json -- expecting %s, got %s", expected, b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"items": {"x": []}}`), &c); err == nil {
+		t.Fatalf("expecting error from bad integer key")
+	}
+}
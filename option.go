@@ -1,31 +1,220 @@
 package gsl
 
-import "github.com/pkg/errors"
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 
-// Rust style option
-type Option[T any] *T
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Rust style option.
+//
+// The zero value is None. Option[T] encodes None as null in JSON and YAML,
+// and as NULL in SQL. With Go 1.24+, use `json:",omitzero"` to omit None fields.
+type Option[T any] struct {
+	value T
+	some  bool
+}
+
+func Some[T any](t T) Option[T] {
+	return Option[T]{value: t, some: true}
+}
+
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
 
 func OptionSome[T any](t T) Option[T] {
-	return &t
+	return Some(t)
 }
 
 func OptionNone[T any]() Option[T] {
-	return nil
+	return None[T]()
 }
 
 func OptionIsNone[T any](o Option[T]) bool {
-	return o == nil
+	return o.IsNone()
 }
 
 func OptionIsSome[T any](o Option[T]) bool {
-	return !OptionIsNone(o)
+	return o.IsSome()
 }
 
 func OptionValue[T any](o Option[T], result *T) error {
-	if o == nil {
-		return errors.New("option is nil")
+	if o.IsNone() {
+		return errors.New("option is none")
+	}
+
+	*result = o.value
+	return nil
+}
+
+// OptionFromPointer returns None if |p| is nil, or Some(*p) otherwise.
+func OptionFromPointer[T any](p *T) Option[T] {
+	if p == nil {
+		return None[T]()
+	}
+
+	return Some(*p)
+}
+
+// OptionMap returns Some(f(value)) if |o| is Some, or None otherwise.
+// It is a function because Go methods cannot have type parameters.
+func OptionMap[T any, U any](o Option[T], f func(T) U) Option[U] {
+	if o.IsNone() {
+		return None[U]()
+	}
+
+	return Some(f(o.value))
+}
+
+// OptionFlatMap returns f(value) if |o| is Some, or None otherwise.
+func OptionFlatMap[T any, U any](o Option[T], f func(T) Option[U]) Option[U] {
+	if o.IsNone() {
+		return None[U]()
+	}
+
+	return f(o.value)
+}
+
+func (o Option[T]) IsSome() bool {
+	return o.some
+}
+
+func (o Option[T]) IsNone() bool {
+	return !o.some
+}
+
+// IsZero reports whether |o| is None, and is used by `json:",omitzero"`.
+func (o Option[T]) IsZero() bool {
+	return o.IsNone()
+}
+
+// Get returns the value and true if |o| is Some,
+// or zero value and false otherwise.
+func (o Option[T]) Get() (T, bool) {
+	return o.value, o.some
+}
+
+// OrElse returns the value if |o| is Some, or |other| otherwise.
+func (o Option[T]) OrElse(other T) T {
+	if o.IsNone() {
+		return other
+	}
+
+	return o.value
+}
+
+// OrElseGet returns the value if |o| is Some, or the result of |f| otherwise.
+func (o Option[T]) OrElseGet(f func() T) T {
+	if o.IsNone() {
+		return f()
+	}
+
+	return o.value
+}
+
+// Filter returns |o| if it is Some and its value satisfies |f|, or None otherwise.
+func (o Option[T]) Filter(f func(T) bool) Option[T] {
+	if o.IsNone() || !f(o.value) {
+		return None[T]()
 	}
 
-	*result = *o
+	return o
+}
+
+// Pointer returns nil if |o| is None, or a pointer to a copy of its value.
+func (o Option[T]) Pointer() *T {
+	if o.IsNone() {
+		return nil
+	}
+
+	v := o.value
+	return &v
+}
+
+func (o Option[T]) String() string {
+	if o.IsNone() {
+		return "None"
+	}
+
+	return fmt.Sprintf("Some(%v)", o.value)
+}
+
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if o.IsNone() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.value)
+}
+
+func (o *Option[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+	return nil
+}
+
+func (o Option[T]) MarshalYAML() (interface{}, error) {
+	if o.IsNone() {
+		return nil, nil
+	}
+
+	return o.value, nil
+}
+
+// UnmarshalYAML decodes non-null values as Some. Note that yaml.v3 does not
+// call unmarshalers for null values, leaving |o| unchanged,
+// so decode into zero values to get None from nulls.
+func (o *Option[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.ShortTag() == "!!null" {
+		*o = None[T]()
+		return nil
+	}
+
+	var value T
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+
+	*o = Some(value)
+	return nil
+}
+
+// Scan implements sql.Scanner: NULL is scanned as None.
+func (o *Option[T]) Scan(src interface{}) error {
+	var null sql.Null[T]
+	if err := null.Scan(src); err != nil {
+		return err
+	}
+
+	if !null.Valid {
+		*o = None[T]()
+		return nil
+	}
+
+	*o = Some(null.V)
 	return nil
 }
+
+// Value implements driver.Valuer: None is NULL, and Some values
+// are converted with driver.DefaultParameterConverter.
+func (o Option[T]) Value() (driver.Value, error) {
+	if o.IsNone() {
+		return nil, nil
+	}
+
+	return driver.DefaultParameterConverter.ConvertValue(o.value)
+}
@@ -1,8 +1,14 @@
 package gsl_test
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/soyart/gsl"
 )
 
@@ -27,3 +33,149 @@ func TestOption(t *testing.T) {
 		t.Fatalf("unexpected some option")
 	}
 }
+
+func TestOptionMethods(t *testing.T) {
+	some := gsl.Some(69)
+	none := gsl.None[int]()
+
+	if v, ok := some.Get(); !ok || v != 69 {
+		t.Fatalf("unexpected Get result %d, %v", v, ok)
+	}
+
+	if _, ok := none.Get(); ok {
+		t.Fatalf("unexpected ok from None")
+	}
+
+	if some.OrElse(1) != 69 || none.OrElse(1) != 1 {
+		t.Fatalf("unexpected OrElse result")
+	}
+
+	if none.OrElseGet(func() int { return 2 }) != 2 {
+		t.Fatalf("unexpected OrElseGet result")
+	}
+
+	isEven := func(i int) bool { return i%2 == 0 }
+	if some.Filter(isEven).IsSome() || gsl.Some(68).Filter(isEven).IsNone() {
+		t.Fatalf("unexpected Filter result")
+	}
+
+	s := gsl.OptionMap(some, func(i int) string { return fmt.Sprintf("%d!", i) })
+	if s.OrElse("") != "69!" {
+		t.Fatalf("unexpected OptionMap result %s", s)
+	}
+
+	if gsl.OptionMap(none, func(i int) string { return "x" }).IsSome() {
+		t.Fatalf("unexpected Some from mapping None")
+	}
+
+	half := func(i int) gsl.Option[int] {
+		if i%2 != 0 {
+			return gsl.None[int]()
+		}
+
+		return gsl.Some(i / 2)
+	}
+
+	if gsl.OptionFlatMap(some, half).IsSome() || gsl.OptionFlatMap(gsl.Some(4), half).OrElse(0) != 2 {
+		t.Fatalf("unexpected OptionFlatMap result")
+	}
+
+	var result int
+	if err := gsl.OptionValue(some, &result); err != nil || result != 69 {
+		t.Fatalf("unexpected OptionValue result %d, %v", result, err)
+	}
+
+	if err := gsl.OptionValue(none, &result); err == nil {
+		t.Fatalf("expecting error from OptionValue on None")
+	}
+
+	// Pointer conversions
+	p := some.Pointer()
+	if p == nil || *p != 69 || none.Pointer() != nil {
+		t.Fatalf("unexpected Pointer result")
+	}
+
+	if gsl.OptionFromPointer(p).OrElse(0) != 69 || gsl.OptionFromPointer[int](nil).IsSome() {
+		t.Fatalf("unexpected OptionFromPointer result")
+	}
+
+	if some.String() != "Some(69)" || none.String() != "None" {
+		t.Fatalf("unexpected String result %s, %s", some, none)
+	}
+}
+
+func TestOptionMarshal(t *testing.T) {
+	type config struct {
+		Name gsl.Option[string] `json:"name" yaml:"name"`
+		Port gsl.Option[int]    `json:"port" yaml:"port"`
+	}
+
+	b, err := json.Marshal(config{Name: gsl.Some("soy")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if s := string(b); s != `{"name":"soy","port":null}` {
+		t.Fatalf("unexpected json %s", s)
+	}
+
+	var c config
+	if err := json.Unmarshal([]byte(`{"name":null,"port":8080}`), &c); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if c.Name.IsSome() || c.Port.OrElse(0) != 8080 {
+		t.Fatalf("unexpected json config %+v", c)
+	}
+
+	if err := json.Unmarshal([]byte(`{"port":"x"}`), &c); err == nil {
+		t.Fatalf("expecting json error")
+	}
+
+	b, err = yaml.Marshal(config{Port: gsl.Some(8080)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if s := string(b); s != "name: null\nport: 8080\n" {
+		t.Fatalf("unexpected yaml %q", s)
+	}
+
+	c = config{}
+	if err := yaml.Unmarshal([]byte("name: ~\nport: 9090\n"), &c); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if c.Name.IsSome() || c.Port.OrElse(0) != 9090 {
+		t.Fatalf("unexpected yaml config %+v", c)
+	}
+}
+
+func TestOptionSQL(t *testing.T) {
+	var o gsl.Option[int64]
+	if err := o.Scan(int64(69)); err != nil || o.OrElse(0) != 69 {
+		t.Fatalf("unexpected Scan result %s, %v", o, err)
+	}
+
+	if err := o.Scan(nil); err != nil || o.IsSome() {
+		t.Fatalf("unexpected Scan result from NULL %s, %v", o, err)
+	}
+
+	var s gsl.Option[string]
+	if err := s.Scan([]byte("foo")); err != nil || s.OrElse("") != "foo" {
+		t.Fatalf("unexpected Scan result %s, %v", s, err)
+	}
+
+	v, err := gsl.Some(69).Value()
+	if err != nil || v != int64(69) {
+		t.Fatalf("unexpected Value result %v (%T), %v", v, v, err)
+	}
+
+	v, err = gsl.None[int]().Value()
+	if err != nil || v != nil {
+		t.Fatalf("unexpected Value result from None %v, %v", v, err)
+	}
+
+	var _ sql.Scanner = &s
+	var _ driver.Valuer = s
+}
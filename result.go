@@ -0,0 +1,172 @@
+package gsl
+
+import (
+	"fmt"
+)
+
+// Rust style result, which carries either a value or an error.
+//
+// Result[T] is useful for collecting per-item errors in pipelines,
+// e.g. with TryMap, instead of failing the whole batch.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+func Ok[T any](t T) Result[T] {
+	return Result[T]{value: t}
+}
+
+// Err returns Result with error |err|. Note that Err(nil) is Ok with zero value.
+func Err[T any](err error) Result[T] {
+	return Result[T]{err: err}
+}
+
+// ResultOf returns Result from (T, error) tuple, e.g. from RetryWithReturn:
+//
+//	r := ResultOf(RetryWithReturn("fetch", fetch, Attempts(3)))
+func ResultOf[T any](t T, err error) Result[T] {
+	if err != nil {
+		return Err[T](err)
+	}
+
+	return Ok(t)
+}
+
+// ResultMap returns Ok(f(value)) if |r| is Ok, or |r|'s error otherwise.
+// It is a function because Go methods cannot have type parameters.
+func ResultMap[T any, U any](r Result[T], f func(T) U) Result[U] {
+	if r.IsErr() {
+		return Err[U](r.err)
+	}
+
+	return Ok(f(r.value))
+}
+
+// ResultAndThen returns f(value) if |r| is Ok, or |r|'s error otherwise.
+func ResultAndThen[T any, U any](r Result[T], f func(T) Result[U]) Result[U] {
+	if r.IsErr() {
+		return Err[U](r.err)
+	}
+
+	return f(r.value)
+}
+
+// Collect returns Ok with all values if all |results| are Ok,
+// or the first error otherwise.
+func Collect[T any](results []Result[T]) Result[[]T] {
+	values := make([]T, len(results))
+	for i := range results {
+		if results[i].IsErr() {
+			return Err[[]T](results[i].err)
+		}
+
+		values[i] = results[i].value
+	}
+
+	return Ok(values)
+}
+
+// PartitionResults splits |results| into values of Ok results
+// and errors of Err results, preserving their order.
+func PartitionResults[T any](results []Result[T]) ([]T, []error) {
+	var values []T
+	var errs []error
+
+	for i := range results {
+		if results[i].IsErr() {
+			errs = append(errs, results[i].err)
+			continue
+		}
+
+		values = append(values, results[i].value)
+	}
+
+	return values, errs
+}
+
+// TryMap is like Map, but maps each of |arr| to Result,
+// so that errors are kept per item instead of failing the whole batch.
+func TryMap[T any, U any](arr []T, f func(elem T) (U, error)) []Result[U] {
+	if arr == nil {
+		return nil
+	}
+
+	results := make([]Result[U], len(arr))
+	for i := range arr {
+		results[i] = ResultOf(f(arr[i]))
+	}
+
+	return results
+}
+
+// RetryResult is like RetryWithReturn, but for functions returning Result.
+func RetryResult[T any](action string, f func() Result[T], opts ...RetryOption) Result[T] {
+	return ResultOf(RetryWithReturn(action, func() (T, error) {
+		return f().Get()
+	},
+		opts...,
+	))
+}
+
+func (r Result[T]) IsOk() bool {
+	return r.err == nil
+}
+
+func (r Result[T]) IsErr() bool {
+	return r.err != nil
+}
+
+// Get returns the value and error as a tuple.
+func (r Result[T]) Get() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the error, or nil if |r| is Ok.
+func (r Result[T]) Err() error {
+	return r.err
+}
+
+// Unwrap returns the value, and panics if |r| is Err.
+func (r Result[T]) Unwrap() T {
+	if r.IsErr() {
+		panic(fmt.Sprintf("unwrap on Err result: %s", r.err.Error()))
+	}
+
+	return r.value
+}
+
+// UnwrapOr returns the value if |r| is Ok, or |other| otherwise.
+func (r Result[T]) UnwrapOr(other T) T {
+	if r.IsErr() {
+		return other
+	}
+
+	return r.value
+}
+
+// UnwrapOrElse returns the value if |r| is Ok, or f(err) otherwise.
+func (r Result[T]) UnwrapOrElse(f func(error) T) T {
+	if r.IsErr() {
+		return f(r.err)
+	}
+
+	return r.value
+}
+
+// Option returns Some(value) if |r| is Ok, or None otherwise.
+func (r Result[T]) Option() Option[T] {
+	if r.IsErr() {
+		return None[T]()
+	}
+
+	return Some(r.value)
+}
+
+func (r Result[T]) String() string {
+	if r.IsErr() {
+		return fmt.Sprintf("Err(%s)", r.err.Error())
+	}
+
+	return fmt.Sprintf("Ok(%v)", r.value)
+}
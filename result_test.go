@@ -0,0 +1,130 @@
+package gsl
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestResult(t *testing.T) {
+	errFoo := errors.New("foo")
+
+	ok := Ok(69)
+	err := Err[int](errFoo)
+
+	if !ok.IsOk() || ok.IsErr() || !err.IsErr() || err.IsOk() {
+		t.Fatalf("unexpected IsOk/IsErr")
+	}
+
+	if v, e := ok.Get(); v != 69 || e != nil {
+		t.Fatalf("unexpected Get result %d, %v", v, e)
+	}
+
+	if !errors.Is(err.Err(), errFoo) || ok.Err() != nil {
+		t.Fatalf("unexpected Err result")
+	}
+
+	if ok.Unwrap() != 69 || ok.UnwrapOr(1) != 69 || err.UnwrapOr(1) != 1 {
+		t.Fatalf("unexpected Unwrap result")
+	}
+
+	if err.UnwrapOrElse(func(error) int { return 2 }) != 2 {
+		t.Fatalf("unexpected UnwrapOrElse result")
+	}
+
+	if ok.Option().OrElse(0) != 69 || err.Option().IsSome() {
+		t.Fatalf("unexpected Option result")
+	}
+
+	if ok.String() != "Ok(69)" || err.String() != "Err(foo)" {
+		t.Fatalf("unexpected String result %s, %s", ok, err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("expecting panic from Unwrap on Err")
+			}
+		}()
+
+		err.Unwrap()
+	}()
+
+	if Err[int](nil).IsErr() {
+		t.Fatalf("expecting Err(nil) to be Ok")
+	}
+
+	s := ResultMap(ok, strconv.Itoa)
+	if s.Unwrap() != "69" || ResultMap(err, strconv.Itoa).IsOk() {
+		t.Fatalf("unexpected ResultMap result")
+	}
+
+	parse := func(s string) Result[int] {
+		return ResultOf(strconv.Atoi(s))
+	}
+
+	if ResultAndThen(s, parse).Unwrap() != 69 || ResultAndThen(Ok("x"), parse).IsOk() {
+		t.Fatalf("unexpected ResultAndThen result")
+	}
+
+	if !errors.Is(ResultAndThen(Err[string](errFoo), parse).Err(), errFoo) {
+		t.Fatalf("expecting errFoo from ResultAndThen on Err")
+	}
+}
+
+func TestCollectResults(t *testing.T) {
+	results := TryMap([]string{"1", "x", "3", "y"}, strconv.Atoi)
+	if len(results) != 4 {
+		t.Fatalf("unexpected results length %d", len(results))
+	}
+
+	if Collect(results).IsOk() {
+		t.Fatalf("expecting error from Collect")
+	}
+
+	values, errs := PartitionResults(results)
+	if len(values) != 2 || values[0] != 1 || values[1] != 3 || len(errs) != 2 {
+		t.Fatalf("unexpected partitions %v, %v", values, errs)
+	}
+
+	collected := Collect(TryMap([]string{"1", "2"}, strconv.Atoi))
+	if v := collected.Unwrap(); len(v) != 2 || v[0] != 1 || v[1] != 2 {
+		t.Fatalf("unexpected collected values %v", v)
+	}
+
+	if TryMap[string, int](nil, strconv.Atoi) != nil {
+		t.Fatalf("expecting nil from nil input")
+	}
+}
+
+func TestRetryResult(t *testing.T) {
+	errFoo := errors.New("foo")
+
+	var i int
+	r := RetryResult("foo", func() Result[int] {
+		i++
+		if i < 2 {
+			return Err[int](errFoo)
+		}
+
+		return Ok(i)
+	},
+		Attempts(3),
+	)
+
+	if r.Unwrap() != 2 {
+		t.Fatalf("unexpected result %s", r)
+	}
+
+	r = ResultOf(RetryWithReturn("foo", func() (int, error) {
+		return 0, errFoo
+	},
+		Attempts(2),
+		LastErrorOnly(true),
+	))
+
+	if !errors.Is(r.Err(), errFoo) {
+		t.Fatalf("expecting errFoo, got %v", r.Err())
+	}
+}
@@ -0,0 +1,203 @@
+package gsl
+
+// KMP searches for a pattern using the Knuth-Morris-Pratt algorithm,
+// in O(len(text)) time after O(len(pattern)) preprocessing.
+//
+// Offsets are in bytes. Valid UTF-8 patterns never match
+// in the middle of a rune of a valid UTF-8 text.
+type KMP struct {
+	pattern string
+
+	// prefix[i] is the length of the longest proper prefix
+	// of pattern[:i+1] which is also its suffix
+	prefix []int
+}
+
+// BoyerMoore searches for a pattern using the Boyer-Moore algorithm
+// with bad character and strong good suffix rules, which skips parts of the text
+// and is usually faster than KMP for long patterns.
+//
+// Offsets are in bytes. Valid UTF-8 patterns never match
+// in the middle of a rune of a valid UTF-8 text.
+type BoyerMoore struct {
+	pattern string
+
+	// last[c] is the last index of byte c in pattern, or -1
+	last [256]int
+
+	// shift[j] is the shift when pattern[j-1] mismatches,
+	// and shift[0] is the shift after a full match
+	shift []int
+}
+
+func NewKMP(pattern string) *KMP {
+	prefix := make([]int, len(pattern))
+	for i, k := 1, 0; i < len(pattern); i++ {
+		for k > 0 && pattern[i] != pattern[k] {
+			k = prefix[k-1]
+		}
+
+		if pattern[i] == pattern[k] {
+			k++
+		}
+
+		prefix[i] = k
+	}
+
+	return &KMP{pattern: pattern, prefix: prefix}
+}
+
+// Index returns the index of the first occurrence of the pattern in |text|,
+// or -1 if there is none. Like strings.Index, an empty pattern matches at 0.
+func (k *KMP) Index(text string) int {
+	if k.pattern == "" {
+		return 0
+	}
+
+	index := -1
+	k.search(text, func(i int) bool {
+		index = i
+		return false
+	})
+
+	return index
+}
+
+// IndexAll returns indexes of all, possibly overlapping, occurrences
+// of the pattern in |text|. An empty pattern never matches.
+func (k *KMP) IndexAll(text string) []int {
+	var indexes []int
+	k.search(text, func(i int) bool {
+		indexes = append(indexes, i)
+		return true
+	})
+
+	return indexes
+}
+
+// search calls |f| with indexes of occurrences until |f| returns false
+func (k *KMP) search(text string, f func(int) bool) {
+	m := len(k.pattern)
+	if m == 0 {
+		return
+	}
+
+	for i, matched := 0, 0; i < len(text); i++ {
+		for matched > 0 && text[i] != k.pattern[matched] {
+			matched = k.prefix[matched-1]
+		}
+
+		if text[i] == k.pattern[matched] {
+			matched++
+		}
+
+		if matched == m {
+			if !f(i + 1 - m) {
+				return
+			}
+
+			matched = k.prefix[m-1]
+		}
+	}
+}
+
+func NewBoyerMoore(pattern string) *BoyerMoore {
+	m := len(pattern)
+	b := &BoyerMoore{pattern: pattern, shift: make([]int, m+1)}
+
+	for c := range b.last {
+		b.last[c] = -1
+	}
+
+	for i := 0; i < m; i++ {
+		b.last[pattern[i]] = i
+	}
+
+	// border[i] is the start of the widest border of pattern[i:]
+	border := make([]int, m+1)
+
+	i, j := m, m+1
+	border[i] = j
+
+	for i > 0 {
+		for j <= m && pattern[i-1] != pattern[j-1] {
+			if b.shift[j] == 0 {
+				b.shift[j] = j - i
+			}
+
+			j = border[j]
+		}
+
+		i--
+		j--
+		border[i] = j
+	}
+
+	// Suffixes matching only a prefix of the pattern
+	j = border[0]
+	for i := 0; i <= m; i++ {
+		if b.shift[i] == 0 {
+			b.shift[i] = j
+		}
+
+		if i == j {
+			j = border[j]
+		}
+	}
+
+	return b
+}
+
+// Index returns the index of the first occurrence of the pattern in |text|,
+// or -1 if there is none. Like strings.Index, an empty pattern matches at 0.
+func (b *BoyerMoore) Index(text string) int {
+	if b.pattern == "" {
+		return 0
+	}
+
+	index := -1
+	b.search(text, func(i int) bool {
+		index = i
+		return false
+	})
+
+	return index
+}
+
+// IndexAll returns indexes of all, possibly overlapping, occurrences
+// of the pattern in |text|. An empty pattern never matches.
+func (b *BoyerMoore) IndexAll(text string) []int {
+	var indexes []int
+	b.search(text, func(i int) bool {
+		indexes = append(indexes, i)
+		return true
+	})
+
+	return indexes
+}
+
+// search calls |f| with indexes of occurrences until |f| returns false
+func (b *BoyerMoore) search(text string, f func(int) bool) {
+	m := len(b.pattern)
+	if m == 0 {
+		return
+	}
+
+	for s := 0; s <= len(text)-m; {
+		j := m - 1
+		for j >= 0 && b.pattern[j] == text[s+j] {
+			j--
+		}
+
+		if j < 0 {
+			if !f(s) {
+				return
+			}
+
+			s += b.shift[0]
+			continue
+		}
+
+		s += max(b.shift[j+1], j-b.last[text[s+j]])
+	}
+}
@@ -0,0 +1,112 @@
+package gsl
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// indexAllNaive returns indexes of all occurrences of |pattern| in |text|
+func indexAllNaive(text, pattern string) []int {
+	var indexes []int
+	for i := 0; pattern != "" && i+len(pattern) <= len(text); i++ {
+		if text[i:i+len(pattern)] == pattern {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+func TestSearch(t *testing.T) {
+	type test struct {
+		text    string
+		pattern string
+		index   int
+		all     []int
+	}
+
+	tests := []test{
+		{text: "", pattern: "", index: 0, all: nil},
+		{text: "abc", pattern: "", index: 0, all: nil},
+		{text: "", pattern: "a", index: -1, all: nil},
+		{text: "aaaa", pattern: "aa", index: 0, all: []int{0, 1, 2}},
+		{text: "abababcab", pattern: "ababc", index: 2, all: []int{2}},
+		{text: "here is a simple example", pattern: "example", index: 17, all: []int{17}},
+		{text: "ภาษาไทยภาษา", pattern: "ภาษา", index: 0, all: []int{0, 21}},
+		{text: "abc", pattern: "abcd", index: -1, all: nil},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		kmp, bm := NewKMP(test.pattern), NewBoyerMoore(test.pattern)
+
+		if index := kmp.Index(test.text); index != test.index {
+			t.Fatalf("[%d] unexpected KMP index %d, expecting %d", i, index, test.index)
+		}
+
+		if index := bm.Index(test.text); index != test.index {
+			t.Fatalf("[%d] unexpected Boyer-Moore index %d, expecting %d", i, index, test.index)
+		}
+
+		if all := kmp.IndexAll(test.text); !reflect.DeepEqual(all, test.all) {
+			t.Fatalf("[%d] unexpected KMP indexes %v, expecting %v", i, all, test.all)
+		}
+
+		if all := bm.IndexAll(test.text); !reflect.DeepEqual(all, test.all) {
+			t.Fatalf("[%d] unexpected Boyer-Moore indexes %v, expecting %v", i, all, test.all)
+		}
+	}
+}
+
+func TestSearchRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(4, 2))
+	randomString := func(n int) string {
+		var b strings.Builder
+		for ; n > 0; n-- {
+			b.WriteByte("abc"[rng.IntN(3)])
+		}
+
+		return b.String()
+	}
+
+	for i := 0; i < 1000; i++ {
+		text, pattern := randomString(rng.IntN(100)), randomString(1+rng.IntN(8))
+		expected := indexAllNaive(text, pattern)
+
+		if all := NewKMP(pattern).IndexAll(text); !reflect.DeepEqual(all, expected) {
+			t.Fatalf("unexpected KMP indexes of %q in %q: %v, expecting %v", pattern, text, all, expected)
+		}
+
+		if all := NewBoyerMoore(pattern).IndexAll(text); !reflect.DeepEqual(all, expected) {
+			t.Fatalf("unexpected Boyer-Moore indexes of %q in %q: %v, expecting %v", pattern, text, all, expected)
+		}
+	}
+}
+
+var (
+	benchmarkSearchText    = strings.Repeat("the quick brown fox jumps over the lazy dog ", 1000) + "needle in a haystack"
+	benchmarkSearchPattern = "needle in a haystack"
+)
+
+func BenchmarkKMP(b *testing.B) {
+	kmp := NewKMP(benchmarkSearchPattern)
+	for i := 0; i < b.N; i++ {
+		kmp.Index(benchmarkSearchText)
+	}
+}
+
+func BenchmarkBoyerMoore(b *testing.B) {
+	bm := NewBoyerMoore(benchmarkSearchPattern)
+	for i := 0; i < b.N; i++ {
+		bm.Index(benchmarkSearchText)
+	}
+}
+
+func BenchmarkStringsIndex(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		strings.Index(benchmarkSearchText, benchmarkSearchPattern)
+	}
+}
@@ -0,0 +1,496 @@
+package soyutils
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/soyart/gsl"
+)
+
+var ErrDecode = errors.New("decode error")
+
+var (
+	typeDuration        = reflect.TypeFor[time.Duration]()
+	typeTime            = reflect.TypeFor[time.Time]()
+	typeTextUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// FieldError is an error decoding the value at Path, e.g. "servers[0].port".
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError collects all field errors from Decode. It wraps ErrDecode.
+type DecodeError struct {
+	Errors []*FieldError
+}
+
+func (e *DecodeError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i := range e.Errors {
+		msgs[i] = e.Errors[i].Error()
+	}
+
+	return fmt.Sprintf("%s: %d field error(s): %s", ErrDecode.Error(), len(e.Errors), strings.Join(msgs, "; "))
+}
+
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+type decodeConfig struct {
+	tagNames      []string
+	defaultTag    string
+	weak          bool
+	errorOnUnused bool
+}
+
+type DecodeOption func(*decodeConfig)
+
+// TagNames sets struct tags used for key names, in order of precedence.
+// The default is "json", then "yaml".
+func TagNames(names ...string) DecodeOption {
+	return func(conf *decodeConfig) {
+		conf.tagNames = names
+	}
+}
+
+// DefaultTag sets the struct tag with default values for missing keys.
+// The default is "default", e.g. `default:"8080"`.
+func DefaultTag(name string) DecodeOption {
+	return func(conf *decodeConfig) {
+		conf.defaultTag = name
+	}
+}
+
+// WeaklyTyped allows conversions between strings, numbers and bools,
+// e.g. "8080" to int or true to "true", with gsl.ConvertValue.
+func WeaklyTyped() DecodeOption {
+	return func(conf *decodeConfig) {
+		conf.weak = true
+	}
+}
+
+// ErrorUnused reports keys that do not match any field as errors.
+func ErrorUnused() DecodeOption {
+	return func(conf *decodeConfig) {
+		conf.errorOnUnused = true
+	}
+}
+
+// Decode fills struct |out| with |m|, e.g. from ReadFileYAML[map[string]interface{}].
+//
+// Keys are matched to fields by struct tags (see TagNames), with options such as
+// ",omitempty" ignored and "-" skipping the field. Untagged fields match keys
+// case-insensitively. Embedded structs without tag names have their fields promoted,
+// like encoding/json. Nested maps are decoded into struct, pointer-to-struct and
+// map fields, and lists into slice and array fields, element-wise.
+//
+// Missing (or null) keys leave fields unchanged, unless a default is set by the default tag
+// (see DefaultTag). Defaults are converted with gsl.ConvertValue, and defaults
+// of slices are comma-separated.
+//
+// By default, values convert only within the same kind, e.g. float64 3 to int,
+// but not "3" to int. Strings convert to time.Duration, time.Time and
+// encoding.TextUnmarshaler fields. See WeaklyTyped for more lenient conversions.
+//
+// Decode does not stop at the first error: it returns *DecodeError with
+// all field errors and their paths, e.g. "servers[0].port".
+func Decode[T any](m map[string]interface{}, out *T, opts ...DecodeOption) error {
+	conf := decodeConfig{
+		tagNames:   []string{"json", "yaml"},
+		defaultTag: "default",
+	}
+
+	for _, opt := range opts {
+		opt(&conf)
+	}
+
+	if out == nil {
+		return errors.Wrap(ErrDecode, "nil output pointer")
+	}
+
+	v := reflect.ValueOf(out).Elem()
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return errors.Wrapf(ErrDecode, "cannot decode into %s, expecting a struct", v.Type())
+	}
+
+	d := decoder{conf: conf}
+	d.decodeStruct("", m, v)
+
+	if len(d.errs) != 0 {
+		return &DecodeError{Errors: d.errs}
+	}
+
+	return nil
+}
+
+type decoder struct {
+	conf decodeConfig
+	errs []*FieldError
+}
+
+func (d *decoder) fail(path string, err error) {
+	d.errs = append(d.errs, &FieldError{Path: path, Err: err})
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// fieldName returns the key name of |field|, whether it is from a tag,
+// and whether the field is skipped
+func (d *decoder) fieldName(field reflect.StructField) (string, bool, bool) {
+	for _, tagName := range d.conf.tagNames {
+		tag, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+
+		if tag == "-" {
+			return "", false, true
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" && strings.Contains(opts, "inline") {
+			return "", false, false
+		}
+
+		if name != "" {
+			return name, true, false
+		}
+	}
+
+	return field.Name, false, false
+}
+
+// lookup returns value of |name| in |m|, matching case-insensitively if not |exact|
+func lookup(m map[string]interface{}, name string, exact bool) (string, interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return name, v, true
+	}
+
+	if exact {
+		return "", nil, false
+	}
+
+	// Sorted for deterministic results with ambiguous keys
+	keys := gsl.SortedKeys(m)
+	for _, key := range keys {
+		if strings.EqualFold(key, name) {
+			return key, m[key], true
+		}
+	}
+
+	return "", nil, false
+}
+
+func (d *decoder) decodeStruct(path string, m map[string]interface{}, v reflect.Value) {
+	used := make(map[string]bool)
+	d.decodeFields(path, m, v, used)
+
+	if !d.conf.errorOnUnused {
+		return
+	}
+
+	var unused []string
+	for key := range m {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+
+	sort.Strings(unused)
+	for _, key := range unused {
+		d.fail(joinPath(path, key), errors.New("unknown key"))
+	}
+}
+
+// decodeFields decodes |m| into fields of struct |v|, including promoted fields
+// of embedded structs, and marks keys used in |used|
+func (d *decoder) decodeFields(path string, m map[string]interface{}, v reflect.Value, used map[string]bool) {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged, skip := d.fieldName(field)
+		if skip {
+			continue
+		}
+
+		fieldValue := v.Field(i)
+
+		// Promote fields of embedded structs without tag names
+		if field.Anonymous && !tagged {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Pointer && embedded.Type().Elem().Kind() == reflect.Struct {
+				if embedded.IsNil() {
+					if !embedded.CanSet() {
+						continue
+					}
+
+					embedded.Set(reflect.New(embedded.Type().Elem()))
+				}
+
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				d.decodeFields(path, m, embedded, used)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		key, value, ok := lookup(m, name, tagged)
+		if ok {
+			used[key] = true
+		}
+
+		fieldPath := joinPath(path, name)
+		if ok && value != nil {
+			fieldPath = joinPath(path, key)
+			d.decodeValue(fieldPath, value, fieldValue)
+
+			continue
+		}
+
+		if def, ok := field.Tag.Lookup(d.conf.defaultTag); ok {
+			d.decodeDefault(fieldPath, def, fieldValue)
+			continue
+		}
+
+		// Apply defaults of nested structs
+		if fieldValue.Kind() == reflect.Struct && fieldValue.Type() != typeTime {
+			d.decodeStruct(fieldPath, map[string]interface{}{}, fieldValue)
+		}
+	}
+}
+
+func (d *decoder) decodeDefault(path string, def string, v reflect.Value) {
+	var value interface{} = def
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		var elems []interface{}
+		for _, elem := range strings.Split(def, ",") {
+			elems = append(elems, strings.TrimSpace(elem))
+		}
+
+		value = elems
+	}
+
+	converted, err := gsl.ConvertValue(value, v.Type())
+	if err != nil {
+		d.fail(path, errors.Wrapf(err, "bad default %q", def))
+		return
+	}
+
+	v.Set(converted)
+}
+
+func (d *decoder) decodeValue(path string, value interface{}, v reflect.Value) {
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			elem.Elem().Set(v.Elem())
+		}
+
+		errs := len(d.errs)
+		d.decodeValue(path, value, elem.Elem())
+
+		if len(d.errs) == errs {
+			v.Set(elem)
+		}
+
+		return
+
+	case reflect.Struct:
+		if v.Type() == typeTime || reflect.PointerTo(v.Type()).Implements(typeTextUnmarshaler) {
+			break
+		}
+
+		m, ok := toStringMap(value)
+		if !ok {
+			d.fail(path, errors.Errorf("expecting a map for %s, got %T", v.Type(), value))
+			return
+		}
+
+		d.decodeStruct(path, m, v)
+		return
+
+	case reflect.Slice, reflect.Array:
+		elems := reflect.ValueOf(value)
+		if elems.Kind() != reflect.Slice && elems.Kind() != reflect.Array {
+			// []byte from string
+			if _, ok := value.(string); ok && v.Type().Elem().Kind() == reflect.Uint8 {
+				break
+			}
+
+			d.fail(path, errors.Errorf("expecting a list for %s, got %T", v.Type(), value))
+			return
+		}
+
+		out := v
+		if v.Kind() == reflect.Slice {
+			out = reflect.MakeSlice(v.Type(), elems.Len(), elems.Len())
+		} else if elems.Len() != v.Len() {
+			d.fail(path, errors.Errorf("expecting %d elements, got %d", v.Len(), elems.Len()))
+			return
+		}
+
+		errs := len(d.errs)
+		for i := 0; i < elems.Len(); i++ {
+			d.decodeValue(fmt.Sprintf("%s[%d]", path, i), elems.Index(i).Interface(), out.Index(i))
+		}
+
+		if len(d.errs) == errs {
+			v.Set(out)
+		}
+
+		return
+
+	case reflect.Map:
+		elems := reflect.ValueOf(value)
+		if elems.Kind() != reflect.Map {
+			d.fail(path, errors.Errorf("expecting a map for %s, got %T", v.Type(), value))
+			return
+		}
+
+		out := reflect.MakeMapWithSize(v.Type(), elems.Len())
+		errs := len(d.errs)
+
+		iter := elems.MapRange()
+		for iter.Next() {
+			elemPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+
+			key, err := gsl.ConvertValue(iter.Key().Interface(), v.Type().Key())
+			if err != nil {
+				d.fail(elemPath, err)
+				continue
+			}
+
+			elem := reflect.New(v.Type().Elem()).Elem()
+			d.decodeValue(elemPath, iter.Value().Interface(), elem)
+			out.SetMapIndex(key, elem)
+		}
+
+		if len(d.errs) == errs {
+			v.Set(out)
+		}
+
+		return
+	}
+
+	if !d.conf.weak {
+		if err := checkStrict(value, v.Type()); err != nil {
+			d.fail(path, err)
+			return
+		}
+	}
+
+	converted, err := gsl.ConvertValue(value, v.Type())
+	if err != nil {
+		d.fail(path, err)
+		return
+	}
+
+	v.Set(converted)
+}
+
+type valueClass uint8
+
+const (
+	classOther valueClass = iota
+	classBool
+	classNumber
+	classString
+)
+
+func classOf(k reflect.Kind) valueClass {
+	switch {
+	case k == reflect.Bool:
+		return classBool
+	case k >= reflect.Int && k <= reflect.Complex128:
+		return classNumber
+	case k == reflect.String:
+		return classString
+	}
+
+	return classOther
+}
+
+// checkStrict rejects conversions between strings, numbers and bools,
+// except strings to types that parse strings
+func checkStrict(value interface{}, target reflect.Type) error {
+	from := classOf(reflect.TypeOf(value).Kind())
+	if from == classString && (target == typeDuration || target == typeTime || reflect.PointerTo(target).Implements(typeTextUnmarshaler)) {
+		return nil
+	}
+
+	to := classOf(target.Kind())
+	if from != classOther && to != classOther && from != to {
+		return errors.Errorf("cannot use %T value %v as %s without WeaklyTyped", value, value, target)
+	}
+
+	return nil
+}
+
+// toStringMap returns |value| as map[string]interface{}, converting
+// other maps with keys formatted with fmt, e.g. from yaml.v2
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	m := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		m[fmt.Sprint(iter.Key().Interface())] = iter.Value().Interface()
+	}
+
+	return m, true
+}
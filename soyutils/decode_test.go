@@ -0,0 +1,159 @@
+package soyutils
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type testBase struct {
+	Name    string `yaml:"name"`
+	Verbose bool   `yaml:"verbose" default:"true"`
+}
+
+type testServer struct {
+	Host    string        `yaml:"host"`
+	Port    uint16        `yaml:"port" default:"8080"`
+	Timeout time.Duration `yaml:"timeout" default:"30s"`
+}
+
+type testConfig struct {
+	testBase `yaml:",inline"`
+
+	Server   testServer            `yaml:"server"`
+	Replicas []*testServer         `yaml:"replicas"`
+	Tags     []string              `yaml:"tags" default:"a, b"`
+	Limits   map[string]int        `yaml:"limits"`
+	Addr     netip.Addr            `yaml:"addr"`
+	Backup   *testServer           `yaml:"backup"`
+	Extra    map[string]testServer `yaml:"extra"`
+	Ignored  string                `yaml:"-"`
+	Level    int
+}
+
+const testDecodeYAML = `
+name: prod
+server:
+  host: example.com
+  timeout: 1m
+replicas:
+  - host: r0
+    port: 9000
+  - host: r1
+limits:
+  cpu: 4
+  mem: 2048
+addr: 10.0.0.1
+extra:
+  metrics:
+    host: m
+Ignored: foo
+LEVEL: 3
+`
+
+func TestDecode(t *testing.T) {
+	var m map[string]interface{}
+	if err := yaml.Unmarshal([]byte(testDecodeYAML), &m); err != nil {
+		t.Fatalf("unexpected yaml error: %v", err)
+	}
+
+	var c testConfig
+	if err := Decode(m, &c); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+
+	expected := testConfig{
+		testBase: testBase{Name: "prod", Verbose: true},
+		Server:   testServer{Host: "example.com", Port: 8080, Timeout: time.Minute},
+		Replicas: []*testServer{
+			{Host: "r0", Port: 9000, Timeout: 30 * time.Second},
+			{Host: "r1", Port: 8080, Timeout: 30 * time.Second},
+		},
+		Tags:   []string{"a", "b"},
+		Limits: map[string]int{"cpu": 4, "mem": 2048},
+		Addr:   netip.MustParseAddr("10.0.0.1"),
+		Extra:  map[string]testServer{"metrics": {Host: "m", Port: 8080, Timeout: 30 * time.Second}},
+		Level:  3,
+	}
+
+	if !reflect.DeepEqual(expected, c) {
+		t.Logf("Expecting %+v", expected)
+		t.Fatalf("unexpected config %+v", c)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	m := map[string]interface{}{
+		"name": 69,
+		"server": map[string]interface{}{
+			"port":    "8080",
+			"timeout": "forever",
+		},
+		"replicas": []interface{}{
+			map[string]interface{}{"port": 70000},
+			"bad",
+		},
+		"unknown": true,
+	}
+
+	var c testConfig
+	err := Decode(m, &c, ErrorUnused())
+
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || !errors.Is(err, ErrDecode) {
+		t.Fatalf("expecting DecodeError, got %v", err)
+	}
+
+	var paths []string
+	for _, fieldErr := range decodeErr.Errors {
+		paths = append(paths, fieldErr.Path)
+	}
+
+	expected := []string{"name", "server.port", "server.timeout", "replicas[0].port", "replicas[1]", "unknown"}
+	if !reflect.DeepEqual(expected, paths) {
+		t.Logf("Error: %s", err.Error())
+		t.Fatalf("unexpected error paths -- expecting %v, got %v", expected, paths)
+	}
+
+	// Weakly typed mode converts strings and numbers
+	c = testConfig{}
+	err = Decode(map[string]interface{}{
+		"name":   69,
+		"server": map[string]interface{}{"port": "8081"},
+		"tags":   []interface{}{1, true},
+	}, &c, WeaklyTyped())
+
+	if err != nil {
+		t.Fatalf("unexpected error in weakly typed mode: %v", err)
+	}
+
+	if c.Name != "69" || c.Server.Port != 8081 || strings.Join(c.Tags, ",") != "1,true" {
+		t.Fatalf("unexpected weakly typed result %+v", c)
+	}
+}
+
+func TestDecodeTagNames(t *testing.T) {
+	type config struct {
+		Host string `json:"host" toml:"hostname"`
+		Port int    `json:"port" env:"PORT"`
+	}
+
+	var c config
+	err := Decode(map[string]interface{}{"hostname": "a", "port": 1.0}, &c, TagNames("toml", "json"), DefaultTag("env"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.Host != "a" || c.Port != 1 {
+		t.Fatalf("unexpected config %+v", c)
+	}
+
+	if err := Decode(map[string]interface{}{"port": 1.5}, &c); !errors.Is(err, ErrDecode) {
+		t.Fatalf("expecting lossy conversion error, got %v", err)
+	}
+}
@@ -0,0 +1,412 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadBulkFormat = errors.New("bad bulk format")
+	ErrBulkColumns   = errors.New("item columns do not match bulk columns")
+)
+
+// ModelCreateSeq is an iterator of ModelCreate, with the same signature
+// as iter.Seq[ModelCreate], so that large inputs can be streamed.
+type ModelCreateSeq func(yield func(ModelCreate) bool)
+
+// SeqModels returns ModelCreateSeq over |items|.
+func SeqModels(items ...ModelCreate) ModelCreateSeq {
+	return func(yield func(ModelCreate) bool) {
+		for i := range items {
+			if !yield(items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// BulkFormat is the data format of bulk load streams.
+type BulkFormat uint8
+
+const (
+	CopyCSV  BulkFormat = iota + 1 // Postgres COPY with "format csv"
+	CopyText                       // Postgres COPY default text format
+	LoadData                       // MySQL LOAD DATA default tab-separated format
+)
+
+func (f BulkFormat) IsValid() bool {
+	switch f {
+	case CopyCSV, CopyText, LoadData:
+		return true
+	}
+
+	return false
+}
+
+// CopyStatement returns Postgres "copy ... from stdin" statement for
+// data written with CopyCSV or CopyText format, e.g. with pgx's CopyFrom.
+func CopyStatement(table string, columns []string, format BulkFormat) (string, error) {
+	if format != CopyCSV && format != CopyText {
+		return "", fmt.Errorf("%w: %d is not a copy format", ErrBadBulkFormat, format)
+	}
+
+	name, err := Postgres.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	clauseColumns, err := ClauseColumnsQuoted(Postgres, columns)
+	if err != nil {
+		return "", err
+	}
+
+	stmt := fmt.Sprintf("copy %s %s from stdin", name, clauseColumns)
+	if format == CopyCSV {
+		stmt += " with (format csv)"
+	}
+
+	return stmt, nil
+}
+
+// LoadDataStatement returns MySQL "load data local infile" statement for
+// data written with LoadData format. |file| is the file name, or "Reader::<name>"
+// for readers registered with go-sql-driver/mysql's RegisterReaderHandler.
+func LoadDataStatement(file, table string, columns []string) (string, error) {
+	if file == "" || strings.ContainsAny(file, "'\\\n") {
+		return "", fmt.Errorf("bad file name %q", file)
+	}
+
+	name, err := MySQL.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	clauseColumns, err := ClauseColumnsQuoted(MySQL, columns)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
+		"load data local infile '%s' into table %s character set utf8mb4"+
+			" fields terminated by '\\t' escaped by '\\\\' lines terminated by '\\n' %s",
+		file, name, clauseColumns,
+	), nil
+}
+
+// WriteBulk writes rows from |seq| to |w| in |format|, and returns the number of rows written.
+// Each item must have exactly |columns| as its ColumnsCreate, otherwise ErrBulkColumns is returned.
+//
+// Values are formatted as text: nil (including nil pointers and invalid sql.Null* values)
+// becomes NULL, driver.Valuer values are formatted from their Value,
+// times use "2006-01-02 15:04:05.999999999-07:00", and []byte use Postgres hex format
+// ("\x0102") for copy formats.
+func WriteBulk(w io.Writer, format BulkFormat, columns []string, seq ModelCreateSeq) (int, error) {
+	if !format.IsValid() {
+		return 0, fmt.Errorf("%w: %d", ErrBadBulkFormat, format)
+	}
+
+	var rows int
+	var err error
+	var buf []byte
+
+	seq(func(item ModelCreate) bool {
+		if !equalColumns(columns, item.ColumnsCreate()) {
+			err = fmt.Errorf("%w: row %d has columns %v, expecting %v", ErrBulkColumns, rows, item.ColumnsCreate(), columns)
+			return false
+		}
+
+		buf, err = appendBulkRow(buf[:0], format, item.ValuesCreate())
+		if err != nil {
+			err = fmt.Errorf("row %d: %w", rows, err)
+			return false
+		}
+
+		if _, err = w.Write(buf); err != nil {
+			return false
+		}
+
+		rows++
+		return true
+	})
+
+	return rows, err
+}
+
+// BulkReader returns io.ReadCloser streaming WriteBulk output, e.g. for
+// pgx's CopyFrom or go-sql-driver/mysql's RegisterReaderHandler.
+// |seq| is consumed in a new goroutine as the reader is read.
+// Close the reader to stop consuming |seq| early.
+func BulkReader(format BulkFormat, columns []string, seq ModelCreateSeq) io.ReadCloser {
+	r, w := io.Pipe()
+
+	go func() {
+		_, err := WriteBulk(w, format, columns, seq)
+		w.CloseWithError(err)
+	}()
+
+	return r
+}
+
+// ExecChunks is the fallback for bulk loads without COPY or LOAD DATA: it inserts
+// items from |seq| with Insert, |chunkSize| rows per statement, and returns
+// total rows affected. Chunks are executed in order, and already inserted chunks
+// are not rolled back on errors - use a transaction as |execer| if needed.
+//
+// If |chunkSize| is 0, it is computed from the dialect's bind variable limit,
+// e.g. 65535 for Postgres or 2100 for SQL Server. Chunks for SQL Server
+// are capped at 1000 rows, the limit of its VALUES list, and Oracle chunks
+// are inserted with INSERT ALL (see InsertBuilder).
+func ExecChunks(ctx context.Context, execer Execer, dialect Dialect, chunkSize int, seq ModelCreateSeq) (int64, error) {
+	if !dialect.IsValid() {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidDialect, dialect)
+	}
+
+	var total int64
+	var err error
+	var chunk []ModelCreate
+
+	flush := func() bool {
+		if len(chunk) == 0 {
+			return true
+		}
+
+		result, errExec := Exec(ctx, execer, Insert(dialect, chunk...))
+		if errExec != nil {
+			err = fmt.Errorf("failed to insert chunk of %d rows after %d rows: %w", len(chunk), total, errExec)
+			return false
+		}
+
+		n, errAffected := result.RowsAffected()
+		if errAffected != nil {
+			n = int64(len(chunk))
+		}
+
+		total += n
+		chunk = chunk[:0]
+
+		return true
+	}
+
+	seq(func(item ModelCreate) bool {
+		if chunkSize <= 0 {
+			chunkSize = dialect.maxBinds() / max(len(item.ColumnsCreate()), 1)
+		}
+
+		if maxRows := dialect.maxRows(); maxRows != 0 {
+			chunkSize = min(chunkSize, maxRows)
+		}
+
+		chunk = append(chunk, item)
+		if len(chunk) < chunkSize {
+			return true
+		}
+
+		return flush()
+	})
+
+	if err != nil {
+		return total, err
+	}
+
+	flush()
+	return total, err
+}
+
+// maxBinds returns maximum number of bind variables per statement
+func (d Dialect) maxBinds() int {
+	switch d {
+	case SQLite:
+		return 32766
+	case SQLServer:
+		return 2100 - 1
+	}
+
+	return 65535
+}
+
+// maxRows returns maximum number of rows in a VALUES list, or 0 if unlimited
+func (d Dialect) maxRows() int {
+	if d == SQLServer {
+		return 1000
+	}
+
+	return 0
+}
+
+func equalColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func appendBulkRow(buf []byte, format BulkFormat, values []interface{}) ([]byte, error) {
+	sep := byte('\t')
+	if format == CopyCSV {
+		sep = ','
+	}
+
+	for i, value := range values {
+		if i != 0 {
+			buf = append(buf, sep)
+		}
+
+		s, null, err := bulkText(value, format)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case null && format == CopyCSV:
+			// Unquoted empty string is NULL in CSV format
+
+		case null:
+			buf = append(buf, `\N`...)
+
+		case format == CopyCSV:
+			buf = appendCSV(buf, s)
+
+		default:
+			buf = appendEscaped(buf, s, format == LoadData)
+		}
+	}
+
+	return append(buf, '\n'), nil
+}
+
+// appendCSV quotes |s| if it is empty (to distinguish it from NULL),
+// contains special characters, or is the end-of-data marker "\.".
+func appendCSV(buf []byte, s string) []byte {
+	if s != "" && s != `\.` && !strings.ContainsAny(s, ",\"\r\n") {
+		return append(buf, s...)
+	}
+
+	buf = append(buf, '"')
+	buf = append(buf, strings.ReplaceAll(s, `"`, `""`)...)
+
+	return append(buf, '"')
+}
+
+// appendEscaped escapes |s| with backslashes, as used by
+// both Postgres text format and MySQL LOAD DATA.
+func appendEscaped(buf []byte, s string, escapeNUL bool) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			buf = append(buf, `\\`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case 0:
+			if escapeNUL {
+				buf = append(buf, `\0`...)
+				break
+			}
+
+			buf = append(buf, c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return buf
+}
+
+// bulkText formats |v| as text, and reports whether it is NULL
+func bulkText(v interface{}, format BulkFormat) (string, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", true, nil
+
+	case string:
+		return v, false, nil
+
+	case []byte:
+		if v == nil {
+			return "", true, nil
+		}
+
+		if format == LoadData {
+			return string(v), false, nil
+		}
+
+		return `\x` + hex.EncodeToString(v), false, nil
+
+	case bool:
+		if format == LoadData {
+			if v {
+				return "1", false, nil
+			}
+
+			return "0", false, nil
+		}
+
+		return strconv.FormatBool(v), false, nil
+
+	case time.Time:
+		if format == LoadData {
+			// MySQL does not accept time zone offsets in datetime literals
+			return v.Format("2006-01-02 15:04:05.999999"), false, nil
+		}
+
+		return v.Format("2006-01-02 15:04:05.999999999-07:00"), false, nil
+
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "", true, nil
+		}
+
+		value, err := v.Value()
+		if err != nil {
+			return "", false, err
+		}
+
+		return bulkText(value, format)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "", true, nil
+		}
+
+		return bulkText(rv.Elem().Interface(), format)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), false, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), false, nil
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits()), false, nil
+
+	case reflect.String:
+		return rv.String(), false, nil
+
+	case reflect.Bool:
+		return bulkText(rv.Bool(), format)
+	}
+
+	return "", false, fmt.Errorf("unsupported bulk value type %T", v)
+}
@@ -0,0 +1,201 @@
+package sqlquery
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyart/gsl/sqlquery/internal/sqlfake"
+)
+
+type bulkRow struct {
+	ID      int64          `db:"id"`
+	Name    string         `db:"name"`
+	Note    *string        `db:"note"`
+	Active  bool           `db:"active"`
+	Data    []byte         `db:"data"`
+	Created time.Time      `db:"created"`
+	Ref     sql.NullString `db:"ref"`
+}
+
+func bulkRows(t *testing.T) []ModelCreate {
+	note := "a,\"b\"\tc\\d\ne"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	models, err := StructModels("rows",
+		&bulkRow{ID: 1, Name: "", Note: &note, Active: true, Data: []byte{0xde, 0xad}, Created: created, Ref: sql.NullString{String: "r", Valid: true}},
+		&bulkRow{ID: 2, Name: `\.`, Created: created},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	return models
+}
+
+var bulkColumns = []string{"id", "name", "note", "active", "data", "created", "ref"}
+
+func TestWriteBulk(t *testing.T) {
+	type test struct {
+		format   BulkFormat
+		expected string
+	}
+
+	tests := []test{
+		{
+			format: CopyCSV,
+			expected: "1,\"\",\"a,\"\"b\"\"\tc\\d\ne\",true,\\xdead,2024-01-02 03:04:05.6+00:00,r\n" +
+				"2,\"\\.\",,false,,2024-01-02 03:04:05.6+00:00,\n",
+		},
+		{
+			format: CopyText,
+			expected: "1\t\ta,\"b\"\\tc\\\\d\\ne\ttrue\t\\\\xdead\t2024-01-02 03:04:05.6+00:00\tr\n" +
+				"2\t\\\\.\t\\N\tfalse\t\\N\t2024-01-02 03:04:05.6+00:00\t\\N\n",
+		},
+		{
+			format: LoadData,
+			expected: "1\t\ta,\"b\"\\tc\\\\d\\ne\t1\t\xde\xad\t2024-01-02 03:04:05.6\tr\n" +
+				"2\t\\\\.\t\\N\t0\t\\N\t2024-01-02 03:04:05.6\t\\N\n",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		var buf bytes.Buffer
+		n, err := WriteBulk(&buf, test.format, bulkColumns, SeqModels(bulkRows(t)...))
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if n != 2 {
+			t.Fatalf("[%d] unexpected rows %d", i, n)
+		}
+
+		if actual := buf.String(); actual != test.expected {
+			t.Logf("Expecting:\n%q", test.expected)
+			t.Logf("Actual:\n%q", actual)
+
+			t.Fatalf("[%d] unexpected output", i)
+		}
+	}
+
+	// Streaming with BulkReader
+	b, err := io.ReadAll(BulkReader(CopyText, bulkColumns, SeqModels(bulkRows(t)...)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if string(b) != tests[1].expected {
+		t.Fatalf("unexpected reader output %q", b)
+	}
+
+	if _, err := io.ReadAll(BulkReader(CopyCSV, []string{"id"}, SeqModels(bulkRows(t)...))); !errors.Is(err, ErrBulkColumns) {
+		t.Fatalf("expecting ErrBulkColumns, got %v", err)
+	}
+
+	if _, err := WriteBulk(io.Discard, BulkFormat(0), bulkColumns, SeqModels()); !errors.Is(err, ErrBadBulkFormat) {
+		t.Fatalf("expecting ErrBadBulkFormat, got %v", err)
+	}
+}
+
+func TestBulkStatements(t *testing.T) {
+	stmt, err := CopyStatement("public.rows", []string{"id", "name"}, CopyCSV)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	assertEqual(t, "copy statement", `copy "public"."rows" ("id","name") from stdin with (format csv)`, stmt)
+
+	stmt, err = LoadDataStatement("Reader::rows", "rows", []string{"id", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := "load data local infile 'Reader::rows' into table `rows` character set utf8mb4" +
+		` fields terminated by '\t' escaped by '\\' lines terminated by '\n' (` + "`id`,`name`)"
+
+	assertEqual(t, "load data statement", expected, stmt)
+
+	if _, err := CopyStatement("rows", []string{"id"}, LoadData); !errors.Is(err, ErrBadBulkFormat) {
+		t.Fatalf("expecting ErrBadBulkFormat, got %v", err)
+	}
+
+	if _, err := LoadDataStatement("a'; drop table rows", "rows", []string{"id"}); err == nil {
+		t.Fatal("expecting error from bad file name")
+	}
+}
+
+func TestExecChunks(t *testing.T) {
+	fake, db := sqlfake.New(func(_ string, args []driver.NamedValue) (sqlfake.Result, error) {
+		return sqlfake.Result{RowsAffected: int64(len(args) / 2)}, nil
+	})
+	defer db.Close()
+
+	items := make([]ModelCreate, 5)
+	for i := range items {
+		items[i] = &foo{id: uint64(i), name: "x", age: 1}
+	}
+
+	n, err := ExecChunks(context.Background(), db, Postgres, 2, SeqModels(items...))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	queries := fake.Queries()
+	if len(queries) != 3 {
+		t.Fatalf("unexpected number of queries %d", len(queries))
+	}
+
+	if !strings.HasSuffix(queries[2], "values ($1,$2,$3)") {
+		t.Fatalf("unexpected last chunk query %s", queries[2])
+	}
+
+	// foo has 3 columns, so the fake returns 1 row affected per 2 args
+	if n != 3+3+1 {
+		t.Fatalf("unexpected rows affected %d", n)
+	}
+
+	// Default chunk size from bind variable limit
+	if _, err := ExecChunks(context.Background(), db, SQLServer, 0, SeqModels(items...)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if l := len(fake.Queries()); l != 4 {
+		t.Fatalf("unexpected number of queries %d", l)
+	}
+
+	// SQL Server chunks are capped at 1000 rows, even with few columns
+	type pair struct {
+		A int `db:"A"`
+		B int `db:"B"`
+	}
+
+	pairs, err := StructModels("PAIRS", make([]pair, 2500)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if _, err := ExecChunks(context.Background(), db, SQLServer, 2000, SeqModels(pairs...)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if l := len(fake.Queries()); l != 4+3 {
+		t.Fatalf("unexpected number of queries %d", l)
+	}
+
+	// Oracle chunks use INSERT ALL
+	if _, err := ExecChunks(context.Background(), db, Oracle, 0, SeqModels(items...)); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if last := fake.Queries()[len(fake.Queries())-1]; !strings.HasPrefix(last, "insert all into") {
+		t.Fatalf("unexpected oracle query %s", last)
+	}
+}
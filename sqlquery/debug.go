@@ -0,0 +1,384 @@
+package sqlquery
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Debug returns |query| with its |placeholder| bind variables replaced by
+// literal values from |args|, e.g. for logging InsertAll statements.
+//
+// Strings are quoted with single quotes doubled, nil values become NULL,
+// times are formatted as quoted '2006-01-02 15:04:05.999999999-07:00',
+// and driver.Valuer values are formatted from their Value.
+//
+// The result is for reading only, and must never be executed,
+// as escaping rules differ between databases.
+//
+// It returns ErrBadBindCount if bind variables in |query| do not match |args|.
+func Debug(query string, args []interface{}, placeholder Placeholder) (string, error) {
+	if !placeholder.IsValid() {
+		return "", fmt.Errorf("bad placeholder %d", placeholder)
+	}
+
+	var out strings.Builder
+	var count uint
+	used := make([]bool, len(args))
+
+	err := scanBinds(query, placeholder, func(text string, n uint) error {
+		if n == 0 {
+			out.WriteString(text)
+			return nil
+		}
+
+		// Question marks are numbered sequentially
+		if placeholder == QuestionMark {
+			count++
+			n = count
+		}
+
+		if n > uint(len(args)) {
+			return fmt.Errorf("%w: bind variable %s has no arg (%d args)", ErrBadBindCount, text, len(args))
+		}
+
+		used[n-1] = true
+		out.WriteString(Literal(args[n-1]))
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for i := range used {
+		if !used[i] {
+			return "", fmt.Errorf("%w: arg %d is not used", ErrBadBindCount, i+1)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Literal formats |v| as SQL literal for Debug.
+func Literal(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+
+	case string:
+		return quoteLiteral(v)
+
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+
+		return "X'" + hex.EncodeToString(v) + "'"
+
+	case bool:
+		if v {
+			return "TRUE"
+		}
+
+		return "FALSE"
+
+	case time.Time:
+		return quoteLiteral(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+
+	case sql.NamedArg:
+		return Literal(v.Value)
+
+	case sql.Out:
+		return "/* out */ NULL"
+
+	case driver.Valuer:
+		// Typed nil pointers may panic in Value
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return "NULL"
+		}
+
+		value, err := v.Value()
+		if err != nil {
+			return fmt.Sprintf("/* %s */ NULL", strings.ReplaceAll(err.Error(), "*/", "* /"))
+		}
+
+		return Literal(value)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return "NULL"
+		}
+
+		return Literal(rv.Elem().Interface())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())
+
+	case reflect.String:
+		return quoteLiteral(rv.String())
+
+	case reflect.Bool:
+		return Literal(rv.Bool())
+	}
+
+	// Only non-basic kinds get here, so fmt.Stringer is used for e.g. structs,
+	// while e.g. time.Duration and integer enums are printed as numbers above
+	return quoteLiteral(fmt.Sprint(v))
+}
+
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// scanBinds splits |query| into text and bind variables of |placeholder|,
+// calling |f| with either text and n = 0, or the bind variable and its number.
+// For QuestionMark, n is always 1.
+func scanBinds(query string, placeholder Placeholder, f func(text string, n uint) error) error {
+	start := 0
+	flush := func(end int) error {
+		if end > start {
+			return f(query[start:end], 0)
+		}
+
+		return nil
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		if skip := skipQuoted(query, i); skip > i {
+			i = skip
+			continue
+		}
+
+		var prefix string
+		switch placeholder {
+		case QuestionMark:
+			if c == '?' {
+				if err := flush(i); err != nil {
+					return err
+				}
+
+				if err := f("?", 1); err != nil {
+					return err
+				}
+
+				i++
+				start = i
+
+				continue
+			}
+
+		case Dollar:
+			prefix = "$"
+		case Colon:
+			prefix = ":"
+		case AtP:
+			prefix = "@p"
+		}
+
+		// Numbered bind variables, which must not follow "::" casts or identifiers
+		if prefix == "" || !strings.HasPrefix(query[i:], prefix) || (i > 0 && (query[i-1] == ':' || isNameByte(query[i-1], false))) {
+			i++
+			continue
+		}
+
+		end := i + len(prefix)
+		for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+			end++
+		}
+
+		if end == i+len(prefix) {
+			i++
+			continue
+		}
+
+		n, err := strconv.ParseUint(query[i+len(prefix):end], 10, 64)
+		if err != nil || n == 0 {
+			i = end
+			continue
+		}
+
+		if err := flush(i); err != nil {
+			return err
+		}
+
+		if err := f(query[i:end], uint(n)); err != nil {
+			return err
+		}
+
+		i, start = end, end
+	}
+
+	return flush(len(query))
+}
+
+// skipQuoted returns the index after quoted string or comment starting at |i|,
+// or |i| if there is none.
+func skipQuoted(query string, i int) int {
+	c := query[i]
+
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		end := strings.IndexByte(query[i+1:], c)
+		if end == -1 {
+			return len(query)
+		}
+
+		return i + end + 2
+
+	case strings.HasPrefix(query[i:], "--"):
+		end := strings.IndexByte(query[i:], '\n')
+		if end == -1 {
+			return len(query)
+		}
+
+		return i + end + 1
+
+	case strings.HasPrefix(query[i:], "/*"):
+		end := strings.Index(query[i+2:], "*/")
+		if end == -1 {
+			return len(query)
+		}
+
+		return i + end + 4
+	}
+
+	return i
+}
+
+// Pretty formats |query| for reading: major clauses start on new lines,
+// and each row of values is on its own indented line, e.g.
+//
+//	insert into "users" ("id","name")
+//	values
+//	  ($1,$2),
+//	  ($3,$4)
+//	returning "id"
+//
+// Quoted strings and comments are left untouched.
+func Pretty(query string) string {
+	var out []byte
+	var depth int
+	var prev string
+	var inValues bool
+
+	newline := func(indent string) {
+		for len(out) != 0 && out[len(out)-1] == ' ' {
+			out = out[:len(out)-1]
+		}
+
+		if len(out) != 0 && out[len(out)-1] != '\n' {
+			out = append(out, '\n')
+		}
+
+		out = append(out, indent...)
+	}
+
+	for i := 0; i < len(query); {
+		if skip := skipQuoted(query, i); skip > i {
+			out = append(out, query[i:skip]...)
+			i = skip
+
+			continue
+		}
+
+		c := query[i]
+
+		switch {
+		case c == '(':
+			if depth == 0 && inValues {
+				newline("  ")
+			}
+
+			depth++
+
+		case c == ')':
+			depth--
+
+		case depth == 0 && isNameByte(c, true) && (i == 0 || !isNameByte(query[i-1], false)):
+			end := i
+			for end < len(query) && isNameByte(query[end], false) {
+				end++
+			}
+
+			word := strings.ToLower(query[i:end])
+			next := strings.ToLower(nextWord(query[end:]))
+
+			if prettyBreak(word, prev, next) {
+				inValues = false
+				newline("")
+			}
+
+			out = append(out, query[i:end]...)
+			prev = word
+			i = end
+
+			if word == "values" {
+				inValues = true
+			}
+
+			continue
+		}
+
+		out = append(out, c)
+		i++
+	}
+
+	return string(out)
+}
+
+func prettyBreak(word, prev, next string) bool {
+	switch word {
+	case "from", "where", "values", "returning", "output", "having", "limit", "offset", "union", "set":
+		return true
+
+	case "group", "order":
+		return next == "by"
+
+	case "left", "right", "full", "cross":
+		return true
+
+	case "inner", "join":
+		return prev != "left" && prev != "right" && prev != "full" && prev != "cross" && prev != "inner"
+
+	case "on":
+		return next == "conflict" || next == "duplicate"
+
+	case "into":
+		// Oracle insert all
+		return prev != "insert" && prev != "all"
+
+	case "select":
+		return prev != ""
+	}
+
+	return false
+}
+
+func nextWord(s string) string {
+	s = strings.TrimLeft(s, " \t\n")
+
+	end := 0
+	for end < len(s) && isNameByte(s[end], false) {
+		end++
+	}
+
+	return s[:end]
+}
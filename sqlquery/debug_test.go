@@ -0,0 +1,131 @@
+package sqlquery
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+type debugStringer struct{}
+
+func (debugStringer) String() string { return "it's" }
+
+func TestDebug(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	name := "soy"
+	var nilName *string
+
+	type test struct {
+		query       string
+		args        []interface{}
+		placeholder Placeholder
+		expected    string
+	}
+
+	tests := []test{
+		{
+			query:       "insert into t (a,b,c) values ($1,$2,$3),($4,$5,$6)",
+			args:        []interface{}{1, "o'neil", nil, uint8(2), ts, true},
+			placeholder: Dollar,
+			expected:    "insert into t (a,b,c) values (1,'o''neil',NULL),(2,'2024-01-02 03:04:05.6+00:00',TRUE)",
+		},
+		{
+			query:       "select * from t where a = ? and b = '?' and c = ? -- ?",
+			args:        []interface{}{&name, nilName},
+			placeholder: QuestionMark,
+			expected:    "select * from t where a = 'soy' and b = '?' and c = NULL -- ?",
+		},
+		{
+			query:       "select * from t where a = :2 and b = :1 and c::text = :1",
+			args:        []interface{}{sql.NullInt64{Int64: 7, Valid: true}, sql.NullString{}},
+			placeholder: Colon,
+			expected:    "select * from t where a = NULL and b = 7 and c::text = 7",
+		},
+		{
+			query:       "select * from t where a = @p1 and b = @p2 and c = @p3",
+			args:        []interface{}{[]byte{0xde, 0xad}, 1.5, debugStringer{}},
+			placeholder: AtP,
+			expected:    "select * from t where a = X'dead' and b = 1.5 and c = 'it''s'",
+		},
+		{
+			query:       "select * from t where a = $1 and b = $2",
+			args:        []interface{}{2 * time.Second, &debugStringer{}},
+			placeholder: Dollar,
+			expected:    "select * from t where a = 2000000000 and b = 'it''s'",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual, err := Debug(test.query, test.args, test.placeholder)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if actual != test.expected {
+			t.Logf("Expecting:\n\"%s\"", test.expected)
+			t.Logf("Actual:\n\"%s\"", actual)
+
+			t.Fatalf("[%d] unexpected result", i)
+		}
+	}
+
+	if _, err := Debug("a = $1 and b = $2", []interface{}{1}, Dollar); !errors.Is(err, ErrBadBindCount) {
+		t.Fatalf("expecting ErrBadBindCount for missing arg, got %v", err)
+	}
+
+	if _, err := Debug("a = ?", []interface{}{1, 2}, QuestionMark); !errors.Is(err, ErrBadBindCount) {
+		t.Fatalf("expecting ErrBadBindCount for unused arg, got %v", err)
+	}
+}
+
+func TestPretty(t *testing.T) {
+	type test struct {
+		query    string
+		expected string
+	}
+
+	tests := []test{
+		{
+			query: `insert into "users" ("id","name") values ($1,$2),($3,'a (b), c') on conflict ("id") do update set "name" = excluded."name" returning "id"`,
+			expected: `insert into "users" ("id","name")` + "\n" +
+				"values\n" +
+				"  ($1,$2),\n" +
+				`  ($3,'a (b), c')` + "\n" +
+				`on conflict ("id") do update` + "\n" +
+				`set "name" = excluded."name"` + "\n" +
+				`returning "id"`,
+		},
+		{
+			query: "insert all into t (a) values (:1) (:2)",
+			expected: "insert all into t (a)\n" +
+				"values\n" +
+				"  (:1)\n" +
+				"  (:2)",
+		},
+		{
+			query: `select "u"."id", count(p.id) from "users" "u" left join "posts" "p" on p.user_id = u.id where (a in (1, 2)) group by "u"."id" order by "u"."id" asc limit 10`,
+			expected: `select "u"."id", count(p.id)` + "\n" +
+				`from "users" "u"` + "\n" +
+				`left join "posts" "p" on p.user_id = u.id` + "\n" +
+				"where (a in (1, 2))\n" +
+				`group by "u"."id"` + "\n" +
+				`order by "u"."id" asc` + "\n" +
+				"limit 10",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		actual := Pretty(test.query)
+		if actual != test.expected {
+			t.Logf("Expecting:\n%s", test.expected)
+			t.Logf("Actual:\n%s", actual)
+
+			t.Fatalf("[%d] unexpected result", i)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package sqlquery
+
+import "fmt"
+
+// Dialect represents SQL dialects that builders in this package can target.
+type Dialect uint8
+
+const (
+	Postgres Dialect = iota + 1
+	MySQL
+	SQLite
+	Oracle
+	SQLServer
+)
+
+func (d Dialect) IsValid() bool {
+	switch d {
+	case Postgres, MySQL, SQLite, Oracle, SQLServer:
+		return true
+	}
+
+	return false
+}
+
+// Placeholder returns the default bind variable style for the dialect.
+func (d Dialect) Placeholder() Placeholder {
+	switch d {
+	case Postgres:
+		return Dollar
+
+	case MySQL, SQLite:
+		return QuestionMark
+
+	case Oracle:
+		return Colon
+
+	case SQLServer:
+		return AtP
+	}
+
+	panic(badDialect(d))
+}
+
+func (d Dialect) String() string {
+	switch d {
+	case Postgres:
+		return "postgres"
+	case MySQL:
+		return "mysql"
+	case SQLite:
+		return "sqlite"
+	case Oracle:
+		return "oracle"
+	case SQLServer:
+		return "sqlserver"
+	}
+
+	return badDialect(d)
+}
+
+func badDialect(d Dialect) string {
+	return fmt.Sprintf("bad Dialect %d", d)
+}
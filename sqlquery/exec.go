@@ -0,0 +1,245 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/soyart/gsl"
+)
+
+// ErrSerializationFailure can be wrapped by callers to mark an error
+// as retryable by WithTx. See IsSerializationFailure.
+var ErrSerializationFailure = errors.New("serialization failure")
+
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// Builder is anything that builds a query and its bind values,
+// e.g. *SelectBuilder.
+type Builder interface {
+	Build() (string, []interface{}, error)
+}
+
+// BuilderFunc adapts functions to Builder, e.g.
+//
+//	BuilderFunc(func() (string, []interface{}, error) {
+//		return InsertAll(Dollar, items...)
+//	})
+type BuilderFunc func() (string, []interface{}, error)
+
+func (f BuilderFunc) Build() (string, []interface{}, error) {
+	return f()
+}
+
+// Raw returns a Builder that returns |query| and |args| as is.
+func Raw(query string, args ...interface{}) Builder {
+	return BuilderFunc(func() (string, []interface{}, error) {
+		return query, args, nil
+	})
+}
+
+// Execer is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Queryer is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// TxBeginner is implemented by *sql.DB and *sql.Conn
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Exec builds the query from |builder| and executes it with |execer|.
+func Exec(ctx context.Context, execer Execer, builder Builder) (sql.Result, error) {
+	query, args, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := execer.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exec query: %w", err)
+	}
+
+	return result, nil
+}
+
+// QueryRows builds the query from |builder|, executes it with |queryer|,
+// and scans the result rows with ScanRows.
+func QueryRows[T any](ctx context.Context, queryer Queryer, builder Builder) ([]T, error) {
+	query, args, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := queryer.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer rows.Close()
+
+	return ScanRows[T](rows)
+}
+
+// ScanRows scans all |rows| into []T. It does not close |rows|.
+//
+// If T is a struct, result columns are mapped to fields by `db` tags,
+// and every column must map to a field. Otherwise the rows must have
+// exactly 1 column, which is scanned into T.
+func ScanRows[T any](rows *sql.Rows) ([]T, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	dests, err := scanPlan(reflect.TypeOf((*T)(nil)).Elem(), columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []T
+	for rows.Next() {
+		var t T
+		if err := rows.Scan(dests(reflect.ValueOf(&t).Elem())...); err != nil {
+			return nil, fmt.Errorf("failed to scan row %d: %w", len(results), err)
+		}
+
+		results = append(results, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// scanPlan returns a function that returns scan destinations
+// for |columns| in a value of type |t|.
+func scanPlan(t reflect.Type, columns []string) (func(reflect.Value) []interface{}, error) {
+	// Structs without db tags, e.g. time.Time, are scanned as scalars
+	info, err := structInfoOf(t)
+	if err != nil && !errors.Is(err, ErrNotStruct) && !errors.Is(err, ErrNoColumnTags) {
+		return nil, err
+	}
+
+	if err != nil || reflect.PointerTo(t).Implements(scannerType) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("cannot scan %d columns into type %s", len(columns), t.String())
+		}
+
+		return func(v reflect.Value) []interface{} {
+			return []interface{}{v.Addr().Interface()}
+		}, nil
+	}
+
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		field, ok := info.byColumn[column]
+		if !ok {
+			return nil, fmt.Errorf("column %s has no matching field in %s", column, t.String())
+		}
+
+		indexes[i] = info.fields[field].index
+	}
+
+	return func(v reflect.Value) []interface{} {
+		dests := make([]interface{}, len(indexes))
+		for i := range indexes {
+			dests[i] = v.FieldByIndex(indexes[i]).Addr().Interface()
+		}
+
+		return dests
+	}, nil
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// WithTx runs |f| in a transaction, which is committed if |f| returns nil
+// and rolled back otherwise.
+//
+// If |f| or the commit fails with a serialization failure (see IsSerializationFailure),
+// the whole transaction is retried with gsl.Retry. By default, it is attempted 3 times
+// and only the last error is returned - |retryOpts| are applied after the defaults.
+// Other errors are returned right away.
+func WithTx(
+	ctx context.Context,
+	db TxBeginner,
+	opts *sql.TxOptions,
+	f func(*sql.Tx) error,
+	retryOpts ...gsl.RetryOption,
+) error {
+	// Non-retryable error, which breaks the retry loop
+	var errFatal error
+
+	retryOpts = append([]gsl.RetryOption{gsl.Attempts(3), gsl.LastErrorOnly(true)}, retryOpts...)
+
+	err := gsl.Retry("transaction", func() error {
+		err := runTx(ctx, db, opts, f)
+		if err != nil && !IsSerializationFailure(err) {
+			errFatal = err
+			return nil
+		}
+
+		return err
+	},
+		retryOpts...,
+	)
+
+	if errFatal != nil {
+		return errFatal
+	}
+
+	return err
+}
+
+func runTx(ctx context.Context, db TxBeginner, opts *sql.TxOptions, f func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := f(tx); err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			return fmt.Errorf("%w (rollback failed: %s)", err, errRollback.Error())
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsSerializationFailure reports whether |err| is a transient transaction failure.
+// It returns true if |err| wraps ErrSerializationFailure, or if |err| wraps an error
+// with method `SQLState() string` returning serialization_failure (40001)
+// or deadlock_detected (40P01), as with pgx errors.
+func IsSerializationFailure(err error) bool {
+	if errors.Is(err, ErrSerializationFailure) {
+		return true
+	}
+
+	var sqlState interface{ SQLState() string }
+	if errors.As(err, &sqlState) {
+		switch sqlState.SQLState() {
+		case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,193 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soyart/gsl"
+	"github.com/soyart/gsl/sqlquery/internal/sqlfake"
+)
+
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "pg error " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestExec(t *testing.T) {
+	fake, db := sqlfake.New(func(string, []driver.NamedValue) (sqlfake.Result, error) {
+		return sqlfake.Result{RowsAffected: 3}, nil
+	})
+	defer db.Close()
+
+	items := make([]ModelCreate, 3)
+	for i := range items {
+		items[i] = &foo{id: uint64(i), name: "x", age: 1}
+	}
+
+	result, err := Exec(context.Background(), db, BuilderFunc(func() (string, []interface{}, error) {
+		return InsertAll(Dollar, items...)
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if n, _ := result.RowsAffected(); n != 3 {
+		t.Fatalf("unexpected rows affected %d", n)
+	}
+
+	if l := len(fake.Calls); l != 1 {
+		t.Fatalf("unexpected number of calls %d", l)
+	}
+
+	if l := len(fake.Calls[0].Args); l != 9 {
+		t.Fatalf("unexpected number of args %d", l)
+	}
+
+	_, err = Exec(context.Background(), db, BuilderFunc(func() (string, []interface{}, error) {
+		return InsertAll(Dollar)
+	}))
+	if err == nil {
+		t.Fatal("expecting build error")
+	}
+}
+
+func TestQueryRows(t *testing.T) {
+	type row struct {
+		ID      int64  `db:"id"`
+		Name    string `db:"name"`
+		Skipped string
+	}
+
+	now := time.Now()
+	_, db := sqlfake.New(func(query string, _ []driver.NamedValue) (sqlfake.Result, error) {
+		switch query {
+		case `select "id", "name" from "users" where id > $1`:
+			return sqlfake.Result{
+				Columns: []string{"id", "name"},
+				Rows: [][]driver.Value{
+					{int64(1), "a"},
+					{int64(2), []byte("b")},
+				},
+			}, nil
+
+		case "select created_at from users":
+			return sqlfake.Result{
+				Columns: []string{"created_at"},
+				Rows:    [][]driver.Value{{now}},
+			}, nil
+
+		case "select id, unknown from users":
+			return sqlfake.Result{
+				Columns: []string{"id", "unknown"},
+				Rows:    [][]driver.Value{{int64(1), "x"}},
+			}, nil
+		}
+
+		return sqlfake.Result{}, errors.New("unexpected query " + query)
+	})
+	defer db.Close()
+
+	ctx := context.Background()
+	rows, err := QueryRows[row](ctx, db, Select(Postgres, "id", "name").From("users").Where("id > ?", 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	assertEqual(t, "rows", []row{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}, rows)
+
+	// Scalar T
+	times, err := QueryRows[time.Time](ctx, db, Raw("select created_at from users"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(times) != 1 || !times[0].Equal(now) {
+		t.Fatalf("unexpected times %v", times)
+	}
+
+	if _, err := QueryRows[row](ctx, db, Raw("select id, unknown from users")); err == nil {
+		t.Fatal("expecting error from unmapped column")
+	}
+
+	if _, err := QueryRows[int64](ctx, db, Raw("select id, unknown from users")); err == nil {
+		t.Fatal("expecting error from scanning multiple columns into scalar")
+	}
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+
+	var attempts int
+	fake, db := sqlfake.New(func(string, []driver.NamedValue) (sqlfake.Result, error) {
+		attempts++
+		if attempts < 3 {
+			return sqlfake.Result{}, &pgError{code: sqlStateSerializationFailure}
+		}
+
+		return sqlfake.Result{RowsAffected: 1}, nil
+	})
+	defer db.Close()
+
+	update := func(tx *sql.Tx) error {
+		_, err := Exec(ctx, tx, Raw("update foo set a = ?", 1))
+		return err
+	}
+
+	if err := WithTx(ctx, db, nil, update); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if fake.Begins != 3 || fake.Rollbacks != 2 || fake.Commits != 1 {
+		t.Fatalf("unexpected tx counts: begins %d, rollbacks %d, commits %d", fake.Begins, fake.Rollbacks, fake.Commits)
+	}
+
+	// Always failing with serialization failure
+	attempts = -100
+	err := WithTx(ctx, db, nil, update, gsl.Attempts(2))
+	if !IsSerializationFailure(err) {
+		t.Fatalf("expecting serialization failure, got %v", err)
+	}
+
+	if attempts != -98 {
+		t.Fatalf("unexpected attempts %d", attempts+100)
+	}
+
+	// Non-retryable errors are returned right away
+	errFoo := errors.New("foo")
+	var calls int
+	err = WithTx(ctx, db, nil, func(*sql.Tx) error {
+		calls++
+		return errFoo
+	})
+	if !errors.Is(err, errFoo) {
+		t.Fatalf("expecting errFoo, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("unexpected calls %d", calls)
+	}
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	if !IsSerializationFailure(&pgError{code: sqlStateDeadlockDetected}) {
+		t.Fatal("expecting true for deadlock")
+	}
+
+	if !IsSerializationFailure(errors.Join(errors.New("foo"), ErrSerializationFailure)) {
+		t.Fatal("expecting true for wrapped ErrSerializationFailure")
+	}
+
+	if IsSerializationFailure(&pgError{code: "23505"}) {
+		t.Fatal("expecting false for unique violation")
+	}
+
+	if IsSerializationFailure(nil) {
+		t.Fatal("expecting false for nil")
+	}
+}
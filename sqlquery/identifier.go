@@ -0,0 +1,154 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxIdentifierLength = 128
+
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// ValidateIdentifier validates identifier |s|, which may be qualified, e.g. "schema.table".
+//
+// Each dot-separated part must start with a letter or underscore,
+// followed by letters, combining marks, digits, underscores or dollar signs,
+// and must not be longer than 128 characters.
+// Anything else, e.g. whitespace, quotes, semicolons or comments, is rejected.
+func ValidateIdentifier(s string) error {
+	if s == "" {
+		return fmt.Errorf("%w: empty identifier", ErrInvalidIdentifier)
+	}
+
+	for _, part := range strings.Split(s, ".") {
+		if err := validateIdentifierPart(part); err != nil {
+			return fmt.Errorf("%w: %q: %s", ErrInvalidIdentifier, s, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func validateIdentifierPart(part string) error {
+	if part == "" {
+		return errors.New("empty part")
+	}
+
+	if !utf8.ValidString(part) {
+		return errors.New("invalid utf-8")
+	}
+
+	if l := utf8.RuneCountInString(part); l > maxIdentifierLength {
+		return fmt.Errorf("part is too long (%d characters)", l)
+	}
+
+	for i, r := range part {
+		switch {
+		case r == '_', unicode.IsLetter(r):
+		case i != 0 && (r == '$' || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc)):
+		default:
+			return fmt.Errorf("unexpected character %q", r)
+		}
+	}
+
+	return nil
+}
+
+// QuoteIdentifier validates |s| with ValidateIdentifier, and quotes each
+// of its parts for the dialect: "part" (Postgres, SQLite, Oracle), `part` (MySQL)
+// or [part] (SQL Server). A "*" as the last part is left unquoted, e.g. "u.*".
+//
+// Note that quoted identifiers are case-sensitive in most databases.
+func (d Dialect) QuoteIdentifier(s string) (string, error) {
+	if !d.IsValid() {
+		return "", fmt.Errorf("%w: %d", ErrInvalidDialect, d)
+	}
+
+	star := s == "*" || strings.HasSuffix(s, ".*")
+	if star {
+		s = strings.TrimSuffix(strings.TrimSuffix(s, "*"), ".")
+		if s == "" {
+			return "*", nil
+		}
+	}
+
+	if err := ValidateIdentifier(s); err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(s, ".")
+	for i := range parts {
+		parts[i] = d.quote(parts[i])
+	}
+
+	quoted := strings.Join(parts, ".")
+	if star {
+		quoted += ".*"
+	}
+
+	return quoted, nil
+}
+
+// QuoteIdentifiers calls QuoteIdentifier on all |identifiers|.
+func (d Dialect) QuoteIdentifiers(identifiers []string) ([]string, error) {
+	quoted := make([]string, len(identifiers))
+	for i := range identifiers {
+		q, err := d.QuoteIdentifier(identifiers[i])
+		if err != nil {
+			return nil, err
+		}
+
+		quoted[i] = q
+	}
+
+	return quoted, nil
+}
+
+// quote quotes a single identifier part without validation.
+// The closing quote character is escaped by doubling it,
+// so that |part| can never escape the quotes.
+func (d Dialect) quote(part string) string {
+	open, closing := d.quoteChars()
+
+	return string(open) + strings.ReplaceAll(part, string(closing), string(closing)+string(closing)) + string(closing)
+}
+
+func (d Dialect) quoteChars() (rune, rune) {
+	switch d {
+	case MySQL:
+		return '`', '`'
+
+	case SQLServer:
+		return '[', ']'
+	}
+
+	return '"', '"'
+}
+
+// ClauseColumnsQuoted is like ClauseColumns, but validates and quotes |columns|.
+func ClauseColumnsQuoted(dialect Dialect, columns []string) (string, error) {
+	quoted, err := dialect.QuoteIdentifiers(columns)
+	if err != nil {
+		return "", err
+	}
+
+	return ClauseColumns(quoted), nil
+}
+
+// validateModelIdentifiers validates table and column names of |item|
+func validateModelIdentifiers(item ModelCreate) error {
+	if err := ValidateIdentifier(item.TableName()); err != nil {
+		return fmt.Errorf("bad table name: %w", err)
+	}
+
+	for _, column := range item.ColumnsCreate() {
+		if err := ValidateIdentifier(column); err != nil {
+			return fmt.Errorf("bad column name: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package sqlquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	type test struct {
+		dialect  Dialect
+		ident    string
+		expected string
+	}
+
+	tests := []test{
+		{dialect: Postgres, ident: "users", expected: `"users"`},
+		{dialect: Oracle, ident: "HR.EMPLOYEES", expected: `"HR"."EMPLOYEES"`},
+		{dialect: SQLite, ident: "_col$1", expected: `"_col$1"`},
+		{dialect: MySQL, ident: "db.users", expected: "`db`.`users`"},
+		{dialect: SQLServer, ident: "dbo.users", expected: "[dbo].[users]"},
+		{dialect: Postgres, ident: "*", expected: "*"},
+		{dialect: Postgres, ident: "u.*", expected: `"u".*`},
+		{dialect: Postgres, ident: "ชื่อ", expected: `"ชื่อ"`},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		quoted, err := test.dialect.QuoteIdentifier(test.ident)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if quoted != test.expected {
+			t.Fatalf("[%d] unexpected quoted identifier: expecting %s, got %s", i, test.expected, quoted)
+		}
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	bads := []string{
+		"",
+		".",
+		"a.",
+		".a",
+		"a..b",
+		"1abc",
+		"$abc",
+		"a b",
+		"a;b",
+		"a--",
+		"a/*b*/",
+		`a"b`,
+		"a`b",
+		"a]b",
+		"a'b",
+		"a\x00b",
+		"*",
+		"a.*",
+		strings.Repeat("a", maxIdentifierLength+1),
+		string([]byte{0xff}),
+	}
+
+	for _, bad := range bads {
+		if err := ValidateIdentifier(bad); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Fatalf("expecting ErrInvalidIdentifier for %q, got %v", bad, err)
+		}
+	}
+
+	if _, err := Postgres.QuoteIdentifier("*.a"); err == nil {
+		t.Fatal("expecting error for star in the middle")
+	}
+
+	if _, err := Dialect(0).QuoteIdentifier("a"); !errors.Is(err, ErrInvalidDialect) {
+		t.Fatalf("expecting ErrInvalidDialect, got %v", err)
+	}
+}
+
+func TestInsertAllInvalidIdentifiers(t *testing.T) {
+	type bad struct {
+		Name string `db:"NAME) values (1); drop table FOO; --"`
+	}
+
+	items, err := StructModels("FOO", bad{Name: "x"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if _, _, err := InsertAll(Colon, items...); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Fatalf("expecting ErrInvalidIdentifier, got %v", err)
+	}
+
+	if _, _, err := InsertAllOracle(items...); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Fatalf("expecting ErrInvalidIdentifier, got %v", err)
+	}
+}
+
+var dialects = []Dialect{Postgres, MySQL, SQLite, Oracle, SQLServer}
+
+// unquoteParts parses quoted, dot-separated identifier |s|,
+// returning false if any part is not properly quoted,
+// or if anything is left outside of the quotes.
+func unquoteParts(d Dialect, s string) ([]string, bool) {
+	open, closing := d.quoteChars()
+	runes := []rune(s)
+
+	var parts []string
+	i := 0
+	for {
+		if i >= len(runes) || runes[i] != open {
+			return nil, false
+		}
+
+		i++
+		var part strings.Builder
+		for {
+			if i >= len(runes) {
+				return nil, false
+			}
+
+			if runes[i] == closing {
+				if i+1 < len(runes) && runes[i+1] == closing {
+					part.WriteRune(closing)
+					i += 2
+					continue
+				}
+
+				i++
+				break
+			}
+
+			part.WriteRune(runes[i])
+			i++
+		}
+
+		parts = append(parts, part.String())
+
+		if i == len(runes) {
+			return parts, true
+		}
+
+		if runes[i] != '.' {
+			return nil, false
+		}
+
+		i++
+	}
+}
+
+func FuzzQuoteIdentifier(f *testing.F) {
+	seeds := []string{"users", "a.b", `a"b`, "a`b", "a]b", "a]]b", "]", `"`, "", "a;--", "x.*"}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, d := range dialects {
+			// Unvalidated quoting must never allow escaping the quotes
+			if utf8.ValidString(s) {
+				parts, ok := unquoteParts(d, d.quote(s))
+				if !ok || len(parts) != 1 || parts[0] != s {
+					t.Fatalf("%s: %q escaped quoting as %s", d.String(), s, d.quote(s))
+				}
+			}
+
+			quoted, err := d.QuoteIdentifier(s)
+			if err != nil {
+				continue
+			}
+
+			if s == "*" || strings.HasSuffix(s, ".*") {
+				continue
+			}
+
+			parts, ok := unquoteParts(d, quoted)
+			if !ok {
+				t.Fatalf("%s: %q quoted to malformed %s", d.String(), s, quoted)
+			}
+
+			if expected := strings.Split(s, "."); strings.Join(parts, ".") != s || len(parts) != len(expected) {
+				t.Fatalf("%s: %q quoted to %s, parsed as %v", d.String(), s, quoted, parts)
+			}
+		}
+	})
+}
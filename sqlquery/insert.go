@@ -7,11 +7,18 @@ import (
 
 // InsertAll returns query and bind values for INSERT ALL into a table.
 // All members of `items` must map to the same table.
+//
+// Table and column names are validated with ValidateIdentifier, but not quoted.
+// Use InsertBuilder for dialect-aware quoting.
 func InsertAll(placeholder Placeholder, items ...ModelCreate) (string, []interface{}, error) {
 	if len(items) == 0 {
 		return "", nil, errors.New("empty items slice")
 	}
 
+	if err := checkItemsCreate(items); err != nil {
+		return "", nil, err
+	}
+
 	tableName := items[0].TableName()
 	columns := items[0].ColumnsCreate()
 	lenCols := len(columns)
@@ -39,3 +46,37 @@ func InsertAll(placeholder Placeholder, items ...ModelCreate) (string, []interfa
 
 	return query, valuesAll, nil
 }
+
+// checkItemsCreate checks if all items map to the same table and columns
+// and validates the table and column names.
+func checkItemsCreate(items []ModelCreate) error {
+	if err := validateModelIdentifiers(items[0]); err != nil {
+		return err
+	}
+
+	tableName := items[0].TableName()
+	columns := items[0].ColumnsCreate()
+
+	for i := range items {
+		if t := items[i].TableName(); t != tableName {
+			return fmt.Errorf("item %d maps to table %s, expecting %s", i, t, tableName)
+		}
+
+		cols := items[i].ColumnsCreate()
+		if len(cols) != len(columns) {
+			return fmt.Errorf("item %d has %d columns, expecting %d", i, len(cols), len(columns))
+		}
+
+		for j := range cols {
+			if cols[j] != columns[j] {
+				return fmt.Errorf("item %d has column %s at %d, expecting %s", i, cols[j], j, columns[j])
+			}
+		}
+
+		if l := len(items[i].ValuesCreate()); l != len(columns) {
+			return fmt.Errorf("item %d has %d values, expecting %d", i, l, len(columns))
+		}
+	}
+
+	return nil
+}
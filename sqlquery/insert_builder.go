@@ -0,0 +1,345 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNoItems              = errors.New("empty items slice")
+	ErrReturningUnsupported = errors.New("returning clause is not supported")
+	ErrUpsertUnsupported    = errors.New("upsert is not supported")
+	ErrNotModelReturning    = errors.New("model does not implement ModelReturning")
+	ErrReturningCount       = errors.New("returned rows count does not match items")
+)
+
+// ModelReturning is implemented by models that can receive
+// returned columns, e.g. generated IDs. *StructModel implements it.
+type ModelReturning interface {
+	// ReturningDests returns scan destinations for |columns|
+	ReturningDests(columns []string) ([]interface{}, error)
+}
+
+// InsertBuilder builds multi-row INSERT statements for a Dialect,
+// optionally with returned columns and upserts.
+//
+// Unlike InsertAll, table and column names are quoted for the dialect.
+type InsertBuilder struct {
+	dialect   Dialect
+	items     []ModelCreate
+	returning []string
+	conflict  []string
+	upsert    bool
+}
+
+// Insert returns *InsertBuilder for |items|,
+// which must all map to the same table and columns.
+func Insert(dialect Dialect, items ...ModelCreate) *InsertBuilder {
+	return &InsertBuilder{
+		dialect: dialect,
+		items:   items,
+	}
+}
+
+// Returning sets columns to be returned from the inserted row:
+//
+//   - Postgres and SQLite: returning "id"
+//
+//   - SQL Server: output INSERTED.[id]
+//
+//   - Oracle: returning "id" into :n, with sql.Out binds pointing to the model,
+//     which must implement ModelReturning.
+//
+//   - MySQL is not supported.
+//
+// Only 1 item is allowed, because no dialect guarantees the order
+// of rows returned from multi-row inserts, so the rows could not be
+// matched to the items. Build returns ErrReturningUnsupported otherwise.
+//
+// Use InsertReturning or ScanReturning to read the returned values back into the model.
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	b.returning = append(b.returning, columns...)
+	return b
+}
+
+// OnConflictUpdate turns the insert into an upsert, which updates all inserted
+// columns except |conflictColumns| if the row already exists:
+//
+//   - Postgres and SQLite: on conflict (conflictColumns) do update set c = excluded.c,
+//     or do nothing if all columns are |conflictColumns|. Because skipped rows
+//     are not returned, InsertReturning returns ErrReturningCount if the item was skipped.
+//
+//   - MySQL: on duplicate key update c = values(c) - |conflictColumns| are only
+//     excluded from the update, as MySQL uses all unique keys for conflicts.
+//
+//   - Oracle and SQL Server (which use MERGE) are not supported.
+func (b *InsertBuilder) OnConflictUpdate(conflictColumns ...string) *InsertBuilder {
+	b.conflict = conflictColumns
+	b.upsert = true
+
+	return b
+}
+
+func (b *InsertBuilder) Build() (string, []interface{}, error) {
+	if !b.dialect.IsValid() {
+		return "", nil, fmt.Errorf("%w: %d", ErrInvalidDialect, b.dialect)
+	}
+
+	if len(b.items) == 0 {
+		return "", nil, ErrNoItems
+	}
+
+	if err := checkItemsCreate(b.items); err != nil {
+		return "", nil, err
+	}
+
+	// Oracle before 23c rejects multi-row VALUES, so INSERT ALL is used instead,
+	// which supports neither upserts nor returning
+	if b.dialect == Oracle && len(b.items) > 1 {
+		switch {
+		case b.upsert:
+			return "", nil, fmt.Errorf("%w: %s", ErrUpsertUnsupported, b.dialect.String())
+		case len(b.returning) != 0:
+			return "", nil, fmt.Errorf("%w: oracle only supports returning into for single-row inserts", ErrReturningUnsupported)
+		}
+	}
+
+	// Databases do not guarantee the order of returned rows,
+	// so the rows cannot be matched to multiple items
+	if len(b.returning) != 0 && len(b.items) > 1 {
+		return "", nil, fmt.Errorf("%w: %s does not guarantee the order of returned rows for multi-row inserts", ErrReturningUnsupported, b.dialect.String())
+	}
+
+	table, err := b.dialect.QuoteIdentifier(b.items[0].TableName())
+	if err != nil {
+		return "", nil, err
+	}
+
+	columns := b.items[0].ColumnsCreate()
+	quotedColumns, err := b.dialect.QuoteIdentifiers(columns)
+	if err != nil {
+		return "", nil, err
+	}
+
+	returning, err := b.dialect.QuoteIdentifiers(b.returning)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if b.dialect == Oracle && len(b.items) > 1 {
+		// Unlike InsertAllOracle, names are quoted like other dialects
+		query, args := insertAllOracle(table, ClauseColumns(quotedColumns), b.items)
+		return query, args, nil
+	}
+
+	query := fmt.Sprintf("insert into %s %s", table, ClauseColumns(quotedColumns))
+
+	if len(returning) != 0 && b.dialect == SQLServer {
+		for i := range returning {
+			returning[i] = "INSERTED." + returning[i]
+		}
+
+		query += " output " + strings.Join(returning, ",")
+	}
+
+	query += " values "
+
+	var args []interface{}
+	placeholder := b.dialect.Placeholder()
+	lenCols := uint(len(columns))
+
+	for i := range b.items {
+		if i != 0 {
+			query += ","
+		}
+
+		query += ClauseValues(placeholder, uint(len(args)+1), lenCols)
+		args = append(args, b.items[i].ValuesCreate()...)
+	}
+
+	if b.upsert {
+		upsert, err := b.clauseUpsert(columns)
+		if err != nil {
+			return "", nil, err
+		}
+
+		query += upsert
+	}
+
+	if len(returning) == 0 {
+		return query, args, nil
+	}
+
+	switch b.dialect {
+	case Postgres, SQLite:
+		query += " returning " + strings.Join(returning, ",")
+
+	case Oracle:
+		if len(b.items) != 1 {
+			return "", nil, fmt.Errorf("%w: oracle only supports returning into for single-row inserts", ErrReturningUnsupported)
+		}
+
+		model, ok := b.items[0].(ModelReturning)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %T", ErrNotModelReturning, b.items[0])
+		}
+
+		dests, err := model.ReturningDests(b.returning)
+		if err != nil {
+			return "", nil, err
+		}
+
+		binds := make([]string, len(dests))
+		for i := range dests {
+			binds[i] = placeholder.Bind(uint(len(args) + 1))
+			args = append(args, sql.Out{Dest: dests[i]})
+		}
+
+		query += fmt.Sprintf(" returning %s into %s", strings.Join(returning, ","), strings.Join(binds, ","))
+
+	case MySQL:
+		return "", nil, fmt.Errorf("%w: %s", ErrReturningUnsupported, b.dialect.String())
+	}
+
+	return query, args, nil
+}
+
+func (b *InsertBuilder) clauseUpsert(columns []string) (string, error) {
+	conflict := make(map[string]bool, len(b.conflict))
+	for _, c := range b.conflict {
+		conflict[c] = true
+	}
+
+	var sets []string
+	for _, column := range columns {
+		if conflict[column] {
+			continue
+		}
+
+		quoted, err := b.dialect.QuoteIdentifier(column)
+		if err != nil {
+			return "", err
+		}
+
+		switch b.dialect {
+		case MySQL:
+			sets = append(sets, fmt.Sprintf("%s = values(%s)", quoted, quoted))
+		default:
+			sets = append(sets, fmt.Sprintf("%s = excluded.%s", quoted, quoted))
+		}
+	}
+
+	switch b.dialect {
+	case Postgres, SQLite:
+		if len(b.conflict) == 0 {
+			return "", fmt.Errorf("%w: missing conflict columns", ErrUpsertUnsupported)
+		}
+
+		target, err := b.dialect.QuoteIdentifiers(b.conflict)
+		if err != nil {
+			return "", err
+		}
+
+		if len(sets) == 0 {
+			return fmt.Sprintf(" on conflict %s do nothing", ClauseColumns(target)), nil
+		}
+
+		return fmt.Sprintf(" on conflict %s do update set %s", ClauseColumns(target), strings.Join(sets, ",")), nil
+
+	case MySQL:
+		if len(sets) == 0 {
+			return "", fmt.Errorf("%w: no columns to update", ErrUpsertUnsupported)
+		}
+
+		return " on duplicate key update " + strings.Join(sets, ","), nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUpsertUnsupported, b.dialect.String())
+}
+
+// QueryExecer is implemented by *sql.DB, *sql.Tx and *sql.Conn
+type QueryExecer interface {
+	Queryer
+	Execer
+}
+
+// InsertReturning executes |b|, and reads returned columns back into
+// its items, which must implement ModelReturning.
+func InsertReturning(ctx context.Context, db QueryExecer, b *InsertBuilder) error {
+	if len(b.returning) == 0 {
+		return fmt.Errorf("%w: no returning columns", ErrReturningUnsupported)
+	}
+
+	query, args, err := b.Build()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	// Oracle returns via sql.Out binds, which already point to the model
+	if b.dialect == Oracle {
+		if _, err := db.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to exec query: %w", err)
+		}
+
+		return nil
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query: %w", err)
+	}
+
+	defer rows.Close()
+
+	return ScanReturning(rows, b.items...)
+}
+
+// ScanReturning scans each of |rows| into the corresponding model in |items|,
+// i.e. row i is scanned into items[i], which must implement ModelReturning.
+// It returns ErrReturningCount if the number of rows is not len(items).
+// It does not close |rows|.
+//
+// Rows are matched to items by position, so the caller must make sure
+// the rows are in the order of |items|, e.g. by selecting with order by.
+// Rows returned from multi-row inserts have no guaranteed order,
+// which is why InsertBuilder only allows returning from 1 item.
+func ScanReturning(rows *sql.Rows, items ...ModelCreate) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("failed to get columns: %w", err)
+	}
+
+	var i int
+	for ; rows.Next(); i++ {
+		if i >= len(items) {
+			return fmt.Errorf("%w: got more rows than %d items", ErrReturningCount, len(items))
+		}
+
+		model, ok := items[i].(ModelReturning)
+		if !ok {
+			return fmt.Errorf("item %d: %w: %T", i, ErrNotModelReturning, items[i])
+		}
+
+		dests, err := model.ReturningDests(columns)
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+
+		if err := rows.Scan(dests...); err != nil {
+			return fmt.Errorf("failed to scan row %d: %w", i, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	if i != len(items) {
+		return fmt.Errorf("%w: got %d rows for %d items", ErrReturningCount, i, len(items))
+	}
+
+	return nil
+}
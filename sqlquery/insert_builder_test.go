@@ -0,0 +1,230 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/soyart/gsl/sqlquery/internal/sqlfake"
+)
+
+func TestInsertBuilder(t *testing.T) {
+	users := []user{
+		{Audit: Audit{CreatedBy: "admin"}, Name: "a"},
+		{Audit: Audit{CreatedBy: "admin"}, Name: "b"},
+	}
+
+	models, err := StructModels("users", &users[0], &users[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	type test struct {
+		builder        *InsertBuilder
+		expectedQuery  string
+		expectedValues []interface{}
+	}
+
+	tests := []test{
+		{
+			builder:        Insert(Postgres, models...),
+			expectedQuery:  `insert into "users" ("CREATED_BY","NAME") values ($1,$2),($3,$4)`,
+			expectedValues: []interface{}{"admin", "a", "admin", "b"},
+		},
+		{
+			builder:        Insert(Postgres, models[0]).Returning("ID"),
+			expectedQuery:  `insert into "users" ("CREATED_BY","NAME") values ($1,$2) returning "ID"`,
+			expectedValues: []interface{}{"admin", "a"},
+		},
+		{
+			builder: Insert(SQLite, models[0]).OnConflictUpdate("CREATED_BY").Returning("ID", "NAME"),
+			expectedQuery: `insert into "users" ("CREATED_BY","NAME") values (?,?)` +
+				` on conflict ("CREATED_BY") do update set "NAME" = excluded."NAME" returning "ID","NAME"`,
+			expectedValues: []interface{}{"admin", "a"},
+		},
+		{
+			builder:        Insert(Postgres, models[0]).OnConflictUpdate("CREATED_BY", "NAME"),
+			expectedQuery:  `insert into "users" ("CREATED_BY","NAME") values ($1,$2) on conflict ("CREATED_BY","NAME") do nothing`,
+			expectedValues: []interface{}{"admin", "a"},
+		},
+		{
+			builder:        Insert(MySQL, models...).OnConflictUpdate("CREATED_BY"),
+			expectedQuery:  "insert into `users` (`CREATED_BY`,`NAME`) values (?,?),(?,?) on duplicate key update `NAME` = values(`NAME`)",
+			expectedValues: []interface{}{"admin", "a", "admin", "b"},
+		},
+		{
+			builder:        Insert(SQLServer, models[0]).Returning("ID"),
+			expectedQuery:  "insert into [users] ([CREATED_BY],[NAME]) output INSERTED.[ID] values (@p1,@p2)",
+			expectedValues: []interface{}{"admin", "a"},
+		},
+		{
+			builder: Insert(Oracle, models...),
+			expectedQuery: `insert all into "users" ("CREATED_BY","NAME") values (:1,:2)` +
+				` into "users" ("CREATED_BY","NAME") values (:3,:4) select * from dual`,
+			expectedValues: []interface{}{"admin", "a", "admin", "b"},
+		},
+		{
+			builder:        Insert(Postgres, models[0]).OnConflictUpdate("CREATED_BY", "NAME").Returning("ID"),
+			expectedQuery:  `insert into "users" ("CREATED_BY","NAME") values ($1,$2) on conflict ("CREATED_BY","NAME") do nothing returning "ID"`,
+			expectedValues: []interface{}{"admin", "a"},
+		},
+		{
+			builder:        Insert(Oracle, models[0]).Returning("ID"),
+			expectedQuery:  `insert into "users" ("CREATED_BY","NAME") values (:1,:2) returning "ID" into :3`,
+			expectedValues: []interface{}{"admin", "a", sql.Out{Dest: &users[0].ID}},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		query, values, err := test.builder.Build()
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if query != test.expectedQuery {
+			t.Logf("Expecting:\n\"%s\"", test.expectedQuery)
+			t.Logf("Actual:\n\"%s\"", query)
+
+			t.Fatalf("[%d] unexpected query", i)
+		}
+
+		assertEqual(t, "values", test.expectedValues, values)
+	}
+}
+
+func TestInsertBuilderErrors(t *testing.T) {
+	models, err := StructModels("users", &user{Name: "a"}, &user{Name: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	byValue, err := NewStructModel("users", user{Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	type test struct {
+		builder  *InsertBuilder
+		expected error
+	}
+
+	tests := []test{
+		{builder: Insert(Dialect(0), models...), expected: ErrInvalidDialect},
+		{builder: Insert(Postgres), expected: ErrNoItems},
+		{builder: Insert(MySQL, models...).Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(Oracle, models...).Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(Oracle, models...).OnConflictUpdate("ID"), expected: ErrUpsertUnsupported},
+		{builder: Insert(SQLServer, models...).Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(Postgres, models...).Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(SQLite, models...).Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(Postgres, models...).OnConflictUpdate("CREATED_BY", "NAME").Returning("ID"), expected: ErrReturningUnsupported},
+		{builder: Insert(Oracle, byValue).Returning("ID"), expected: ErrNotModelReturning},
+		{builder: Insert(Oracle, &foo{}).Returning("id"), expected: ErrNotModelReturning},
+		{builder: Insert(SQLServer, models...).OnConflictUpdate("ID"), expected: ErrUpsertUnsupported},
+		{builder: Insert(Postgres, models...).OnConflictUpdate(), expected: ErrUpsertUnsupported},
+		{builder: Insert(Postgres, models[0]).Returning("ID; drop table users"), expected: ErrInvalidIdentifier},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		_, _, err := test.builder.Build()
+		if !errors.Is(err, test.expected) {
+			t.Fatalf("[%d] expecting error %v, got %v", i, test.expected, err)
+		}
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	ctx := context.Background()
+
+	fake, db := sqlfake.New(func(query string, args []driver.NamedValue) (sqlfake.Result, error) {
+		switch query {
+		case `insert into "users" ("CREATED_BY","NAME") values ($1,$2) returning "ID","NICKNAME"`:
+			return sqlfake.Result{
+				Columns: []string{"ID", "NICKNAME"},
+				Rows: [][]driver.Value{
+					{int64(1), "aa"},
+				},
+			}, nil
+
+		case `insert into "users" ("CREATED_BY","NAME") values ($1,$2) on conflict ("CREATED_BY","NAME") do nothing returning "ID"`:
+			return sqlfake.Result{Columns: []string{"ID"}}, nil
+
+		case `insert into "users" ("CREATED_BY","NAME") values (:1,:2) returning "ID" into :3`:
+			out, ok := args[2].Value.(sql.Out)
+			if !ok {
+				return sqlfake.Result{}, errors.New("expecting sql.Out")
+			}
+
+			*out.Dest.(*uint64) = 69
+			return sqlfake.Result{RowsAffected: 1}, nil
+		}
+
+		return sqlfake.Result{}, errors.New("unexpected query " + query)
+	})
+	defer db.Close()
+
+	users := []user{{Name: "a"}, {Name: "b"}}
+	models, err := StructModels("users", &users[0], &users[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	// Returned rows cannot be matched to multiple items
+	err = InsertReturning(ctx, db, Insert(Postgres, models...).Returning("ID", "NICKNAME"))
+	if !errors.Is(err, ErrReturningUnsupported) {
+		t.Fatalf("expecting ErrReturningUnsupported, got %v", err)
+	}
+
+	err = InsertReturning(ctx, db, Insert(Postgres, models[0]).Returning("ID", "NICKNAME"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	assertEqual(t, "users", []user{{ID: 1, Name: "a", Nickname: "aa"}, {Name: "b"}}, users)
+
+	// Oracle returns into sql.Out binds
+	u := user{Name: "c"}
+	model, err := NewStructModel("users", &u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if err := InsertReturning(ctx, db, Insert(Oracle, model).Returning("ID")); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if u.ID != 69 {
+		t.Fatalf("unexpected id %d", u.ID)
+	}
+
+	// Handler fails for unknown query
+	err = InsertReturning(ctx, db, Insert(Postgres, models[1]).Returning("ID"))
+	if err == nil {
+		t.Fatal("expecting error from unexpected query")
+	}
+
+	// Skipped rows are not returned
+	skipped, err := NewStructModel("users", &user{Name: "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	err = InsertReturning(ctx, db, Insert(Postgres, skipped).OnConflictUpdate("CREATED_BY", "NAME").Returning("ID"))
+	if !errors.Is(err, ErrReturningCount) {
+		t.Fatalf("expecting ErrReturningCount, got %v", err)
+	}
+
+	if l := len(fake.Calls); l != 4 {
+		t.Fatalf("unexpected number of calls %d", l)
+	}
+
+	if err := InsertReturning(ctx, db, Insert(Postgres, models...)); !errors.Is(err, ErrReturningUnsupported) {
+		t.Fatalf("expecting ErrReturningUnsupported, got %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+// Package sqlfake provides a scriptable fake database/sql driver for tests.
+package sqlfake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Result is what Handler returns for a statement.
+// Columns and Rows are used for queries, RowsAffected and LastInsertID for execs.
+type Result struct {
+	Columns      []string
+	Rows         [][]driver.Value
+	RowsAffected int64
+	LastInsertID int64
+}
+
+// Handler is called for every statement executed against the fake driver.
+type Handler func(query string, args []driver.NamedValue) (Result, error)
+
+// Call records a statement sent to the fake driver.
+type Call struct {
+	Query string
+	Args  []driver.NamedValue
+}
+
+// Driver is a fake driver.Driver and driver.Connector.
+type Driver struct {
+	mut sync.Mutex
+
+	Handler Handler
+	Calls   []Call
+
+	Begins    int
+	Commits   int
+	Rollbacks int
+}
+
+// New returns a fake driver and a *sql.DB connected to it.
+func New(handler Handler) (*Driver, *sql.DB) {
+	d := &Driver{Handler: handler}
+	return d, sql.OpenDB(d)
+}
+
+func (d *Driver) Open(string) (driver.Conn, error) {
+	return &conn{driver: d}, nil
+}
+
+func (d *Driver) Connect(context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *Driver) Driver() driver.Driver {
+	return d
+}
+
+// Queries returns all queries seen by the driver.
+func (d *Driver) Queries() []string {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	queries := make([]string, len(d.Calls))
+	for i := range d.Calls {
+		queries[i] = d.Calls[i].Query
+	}
+
+	return queries
+}
+
+func (d *Driver) handle(query string, args []driver.NamedValue) (Result, error) {
+	d.mut.Lock()
+	d.Calls = append(d.Calls, Call{Query: query, Args: args})
+	handler := d.Handler
+	d.mut.Unlock()
+
+	if handler == nil {
+		return Result{}, nil
+	}
+
+	return handler(query, args)
+}
+
+func (d *Driver) count(counter *int) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	*counter++
+}
+
+type conn struct {
+	driver *Driver
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: c, query: query}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	c.driver.count(&c.driver.Begins)
+	return &tx{driver: c.driver}, nil
+}
+
+func (c *conn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+// CheckNamedValue accepts any value, so that sql.Out and custom types
+// reach the handler unchanged.
+func (c *conn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	result, err := c.driver.handle(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return execResult{
+		lastInsertID: result.LastInsertID,
+		rowsAffected: result.RowsAffected,
+	}, nil
+}
+
+func (c *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	result, err := c.driver.handle(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rows{columns: result.Columns, rows: result.Rows}, nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.ExecContext(context.Background(), s.query, named(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.QueryContext(context.Background(), s.query, named(args))
+}
+
+type tx struct {
+	driver *Driver
+}
+
+func (t *tx) Commit() error {
+	t.driver.count(&t.driver.Commits)
+	return nil
+}
+
+func (t *tx) Rollback() error {
+	t.driver.count(&t.driver.Rollbacks)
+	return nil
+}
+
+type execResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r execResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r execResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+type rows struct {
+	columns []string
+	rows    [][]driver.Value
+	cursor  int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.cursor >= len(r.rows) {
+		return io.EOF
+	}
+
+	row := r.rows[r.cursor]
+	if len(row) != len(dest) {
+		return errors.New("sqlfake: row length does not match columns")
+	}
+
+	copy(dest, row)
+	r.cursor++
+
+	return nil
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	values := make([]driver.NamedValue, len(args))
+	for i := range args {
+		values[i] = driver.NamedValue{Ordinal: i + 1, Value: args[i]}
+	}
+
+	return values
+}
@@ -0,0 +1,542 @@
+// Package migrate provides a simple versioned migration runner for database/sql,
+// using plain SQL up and down files.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soyart/gsl"
+	"github.com/soyart/gsl/data"
+	"github.com/soyart/gsl/sqlquery"
+)
+
+const (
+	DefaultTable     = "schema_migrations"
+	DefaultLockTable = "schema_migrations_lock"
+)
+
+var (
+	ErrBadFilename      = errors.New("bad migration filename")
+	ErrDuplicateVersion = errors.New("duplicate migration version")
+	ErrMissingUp        = errors.New("missing up migration")
+	ErrNoDown           = errors.New("migration has no down migration")
+	ErrUnknownVersion   = errors.New("applied version has no migration")
+	ErrOutOfOrder       = errors.New("pending migration is older than applied migrations")
+	ErrLocked           = errors.New("failed to acquire migration lock")
+)
+
+// e.g. 0001_create_users.up.sql
+var reFilename = regexp.MustCompile(`^(\d+)_([A-Za-z0-9_\-]+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change.
+// Up and Down are SQL statements, and Down may be empty.
+type Migration struct {
+	Version uint64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load loads migrations from files in the root directory of |fsys|
+// named VERSION_NAME.up.sql and VERSION_NAME.down.sql, e.g. "0001_create_users.up.sql".
+// Use fs.Sub to load from subdirectories. Files without ".sql" extension are ignored.
+//
+// The returned migrations are sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(filename, ".sql") {
+			continue
+		}
+
+		matches := reFilename.FindStringSubmatch(filename)
+		if matches == nil {
+			return nil, fmt.Errorf("%w: %s", ErrBadFilename, filename)
+		}
+
+		version, err := strconv.ParseUint(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrBadFilename, filename, err.Error())
+		}
+
+		b, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", filename, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		}
+
+		if m.Name != matches[2] {
+			return nil, fmt.Errorf("%w: %d (%s and %s)", ErrDuplicateVersion, version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingUp, m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// DB is implemented by *sql.DB and *sql.Conn
+type DB interface {
+	sqlquery.TxBeginner
+	sqlquery.QueryExecer
+}
+
+// Migrator applies migrations to a database, and tracks applied versions
+// in a tracking table. Both the tracking table and the lock table are created
+// if they do not exist.
+//
+// To prevent concurrent runs, Migrator holds a lock while migrating,
+// by inserting a row into the lock table. If the process dies while holding
+// the lock, the lock must be released manually with Unlock.
+//
+// Each migration is applied in its own transaction, together with its
+// tracking row. Note that some databases, e.g. MySQL and Oracle,
+// implicitly commit DDL statements, so failed migrations there
+// may be partially applied.
+//
+// Migrations with multiple statements must be supported by the driver,
+// e.g. with "multiStatements=true" for go-sql-driver/mysql.
+type Migrator struct {
+	db         DB
+	dialect    sqlquery.Dialect
+	migrations []Migration
+
+	table        string
+	lockTable    string
+	lockAttempts int
+	lockDelay    time.Duration
+}
+
+type Option func(*Migrator)
+
+// Table sets the tracking table name. Default is DefaultTable.
+func Table(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// LockTable sets the lock table name. Default is DefaultLockTable.
+func LockTable(table string) Option {
+	return func(m *Migrator) {
+		m.lockTable = table
+	}
+}
+
+// LockRetry sets how many times Migrator attempts to acquire the lock,
+// and the delay between attempts. Default is 10 attempts, 1 second apart.
+func LockRetry(attempts int, delay time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockAttempts = attempts
+		m.lockDelay = delay
+	}
+}
+
+// New returns *Migrator for |migrations|, e.g. from Load.
+func New(db DB, dialect sqlquery.Dialect, migrations []Migration, opts ...Option) (*Migrator, error) {
+	if !dialect.IsValid() {
+		return nil, fmt.Errorf("%w: %d", sqlquery.ErrInvalidDialect, dialect)
+	}
+
+	m := &Migrator{
+		db:           db,
+		dialect:      dialect,
+		migrations:   make([]Migration, len(migrations)),
+		table:        DefaultTable,
+		lockTable:    DefaultLockTable,
+		lockAttempts: 10,
+		lockDelay:    time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	copy(m.migrations, migrations)
+	sort.Slice(m.migrations, func(i, j int) bool {
+		return m.migrations[i].Version < m.migrations[j].Version
+	})
+
+	for i := range m.migrations {
+		if i != 0 && m.migrations[i].Version == m.migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, m.migrations[i].Version)
+		}
+	}
+
+	for _, table := range []string{m.table, m.lockTable} {
+		if err := sqlquery.ValidateIdentifier(table); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Up applies all pending migrations, and returns the applied migrations.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	return m.UpTo(ctx, ^uint64(0))
+}
+
+// UpTo applies pending migrations up to and including |version|,
+// and returns the applied migrations.
+//
+// It returns ErrOutOfOrder if a pending migration is older than
+// the latest applied migration.
+func (m *Migrator) UpTo(ctx context.Context, version uint64) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		var latest uint64
+		if len(applied) != 0 {
+			latest = applied[len(applied)-1]
+		}
+
+		isApplied := make(map[uint64]bool, len(applied))
+		for _, v := range applied {
+			isApplied[v] = true
+		}
+
+		for i := range m.migrations {
+			migration := &m.migrations[i]
+			if isApplied[migration.Version] || migration.Version > version {
+				continue
+			}
+
+			if migration.Version < latest {
+				return fmt.Errorf("%w: %d_%s (latest applied %d)", ErrOutOfOrder, migration.Version, migration.Name, latest)
+			}
+
+			if err := m.apply(ctx, migration, true); err != nil {
+				return err
+			}
+
+			done = append(done, *migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Down rolls back the latest |steps| applied migrations,
+// and returns the rolled back migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+
+	err := m.withLock(ctx, func() error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+			// Never nil, as applied checks all versions
+			migration := m.find(applied[i])
+			if migration.Down == "" {
+				return fmt.Errorf("%w: %d_%s", ErrNoDown, migration.Version, migration.Name)
+			}
+
+			if err := m.apply(ctx, migration, false); err != nil {
+				return err
+			}
+
+			done = append(done, *migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+// Version returns the latest applied version, or 0 if nothing was applied.
+func (m *Migrator) Version(ctx context.Context) (uint64, error) {
+	if err := m.ensureTables(ctx); err != nil {
+		return 0, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(applied) == 0 {
+		return 0, nil
+	}
+
+	return applied[len(applied)-1], nil
+}
+
+// Unlock forcibly releases the migration lock, e.g. after a crash.
+func (m *Migrator) Unlock(ctx context.Context) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+
+	return m.unlock(ctx)
+}
+
+// applied returns applied versions in ascending order,
+// and checks that all of them have migrations.
+func (m *Migrator) applied(ctx context.Context) ([]uint64, error) {
+	versions, err := sqlquery.QueryRows[uint64](ctx, m.db, sqlquery.Select(m.dialect, "version").
+		From(m.table).
+		OrderBy("version", data.Ascending),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied versions: %w", err)
+	}
+
+	for _, version := range versions {
+		if m.find(version) == nil {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+	}
+
+	return versions, nil
+}
+
+func (m *Migrator) find(version uint64) *Migration {
+	i := sort.Search(len(m.migrations), func(i int) bool {
+		return m.migrations[i].Version >= version
+	})
+
+	if i < len(m.migrations) && m.migrations[i].Version == version {
+		return &m.migrations[i]
+	}
+
+	return nil
+}
+
+type record struct {
+	Version   uint64    `db:"version"`
+	Name      string    `db:"name"`
+	AppliedAt time.Time `db:"applied_at"`
+}
+
+type lockRecord struct {
+	ID       int       `db:"id"`
+	LockedAt time.Time `db:"locked_at"`
+}
+
+func (m *Migrator) apply(ctx context.Context, migration *Migration, up bool) error {
+	direction, stmt := "up", migration.Up
+	if !up {
+		direction, stmt = "down", migration.Down
+	}
+
+	err := sqlquery.WithTx(ctx, m.db, nil, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+
+		if !up {
+			return m.delete(ctx, tx, m.table, "version", migration.Version)
+		}
+
+		model, err := sqlquery.NewStructModel(m.table, &record{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = sqlquery.Exec(ctx, tx, sqlquery.Insert(m.dialect, model))
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s %d_%s: %w", direction, migration.Version, migration.Name, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, f func() error) error {
+	if err := m.ensureTables(ctx); err != nil {
+		return err
+	}
+
+	if err := m.lock(ctx); err != nil {
+		return err
+	}
+
+	err := f()
+
+	// Release the lock even if ctx was canceled
+	if errUnlock := m.unlock(context.WithoutCancel(ctx)); errUnlock != nil {
+		if err != nil {
+			return fmt.Errorf("%w (unlock failed: %s)", err, errUnlock.Error())
+		}
+
+		return errUnlock
+	}
+
+	return err
+}
+
+func (m *Migrator) lock(ctx context.Context) error {
+	rec := &lockRecord{ID: 1}
+	model, err := sqlquery.NewStructModel(m.lockTable, rec)
+	if err != nil {
+		return err
+	}
+
+	err = gsl.Retry("lock", func() error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rec.LockedAt = time.Now().UTC()
+		_, err := sqlquery.Exec(ctx, m.db, sqlquery.Insert(m.dialect, model))
+		return err
+	},
+		gsl.Attempts(m.lockAttempts),
+		gsl.Delay(m.lockDelay),
+		gsl.LastErrorOnly(true),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrLocked, err.Error())
+	}
+
+	return nil
+}
+
+func (m *Migrator) unlock(ctx context.Context) error {
+	if err := m.delete(ctx, m.db, m.lockTable, "id", 1); err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) delete(ctx context.Context, execer sqlquery.Execer, table, column string, value interface{}) error {
+	quotedTable, err := m.dialect.QuoteIdentifier(table)
+	if err != nil {
+		return err
+	}
+
+	quotedColumn, err := m.dialect.QuoteIdentifier(column)
+	if err != nil {
+		return err
+	}
+
+	query, _ := sqlquery.Rebind(fmt.Sprintf("delete from %s where %s = ?", quotedTable, quotedColumn), m.dialect.Placeholder(), 1)
+	_, err = execer.ExecContext(ctx, query, value)
+
+	return err
+}
+
+// ensureTables creates the tracking table and the lock table if they do not exist.
+// Existence is probed with a select, because "create table if not exists"
+// is not supported by all dialects.
+func (m *Migrator) ensureTables(ctx context.Context) error {
+	tables := []sqlquery.Table{
+		{
+			Name: m.table,
+			Columns: []sqlquery.Column{
+				{Name: "version", Type: sqlquery.TypeBigInt},
+				{Name: "name", Type: sqlquery.TypeVarchar, Size: 255},
+				{Name: "applied_at", Type: sqlquery.TypeTimestamp},
+			},
+			PrimaryKey: []string{"version"},
+		},
+		{
+			Name: m.lockTable,
+			Columns: []sqlquery.Column{
+				{Name: "id", Type: sqlquery.TypeInt},
+				{Name: "locked_at", Type: sqlquery.TypeTimestamp},
+			},
+			PrimaryKey: []string{"id"},
+		},
+	}
+
+	for i := range tables {
+		table := &tables[i]
+
+		if m.exists(ctx, table) {
+			continue
+		}
+
+		stmts, err := sqlquery.CreateTable(m.dialect, *table)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range stmts {
+			if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+				// Another migrator may have just created the table
+				if m.exists(ctx, table) {
+					break
+				}
+
+				return fmt.Errorf("failed to create table %s: %w", table.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Migrator) exists(ctx context.Context, table *sqlquery.Table) bool {
+	query, _, err := sqlquery.Select(m.dialect, table.Columns[0].Name).
+		From(table.Name).
+		Where("1 = 0").
+		Build()
+	if err != nil {
+		return false
+	}
+
+	rows, err := m.db.QueryContext(ctx, query)
+	if err != nil {
+		return false
+	}
+
+	rows.Close()
+	return true
+}
@@ -0,0 +1,261 @@
+package migrate
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/soyart/gsl/sqlquery"
+	"github.com/soyart/gsl/sqlquery/internal/sqlfake"
+)
+
+// fakeDB simulates tracking tables for Postgres queries
+type fakeDB struct {
+	tables   map[string]bool
+	versions []uint64
+	locked   bool
+	executed []string
+}
+
+func (f *fakeDB) handle(query string, args []driver.NamedValue) (sqlfake.Result, error) {
+	switch {
+	case strings.HasPrefix(query, `select "version" from "schema_migrations" where 1 = 0`):
+		if !f.tables["schema_migrations"] {
+			return sqlfake.Result{}, errors.New("no such table")
+		}
+
+		return sqlfake.Result{Columns: []string{"version"}}, nil
+
+	case strings.HasPrefix(query, `select "id" from "schema_migrations_lock" where 1 = 0`):
+		if !f.tables["schema_migrations_lock"] {
+			return sqlfake.Result{}, errors.New("no such table")
+		}
+
+		return sqlfake.Result{Columns: []string{"id"}}, nil
+
+	case strings.HasPrefix(query, `create table "schema_migrations" `):
+		f.tables["schema_migrations"] = true
+
+	case strings.HasPrefix(query, `create table "schema_migrations_lock" `):
+		f.tables["schema_migrations_lock"] = true
+
+	case strings.HasPrefix(query, `insert into "schema_migrations_lock" `):
+		if f.locked {
+			return sqlfake.Result{}, errors.New("duplicate key")
+		}
+
+		f.locked = true
+
+	case query == `delete from "schema_migrations_lock" where "id" = $1`:
+		f.locked = false
+
+	case query == `select "version" from "schema_migrations" order by "version" asc`:
+		rows := make([][]driver.Value, len(f.versions))
+		for i, v := range f.versions {
+			rows[i] = []driver.Value{int64(v)}
+		}
+
+		return sqlfake.Result{Columns: []string{"version"}, Rows: rows}, nil
+
+	case strings.HasPrefix(query, `insert into "schema_migrations" `):
+		f.versions = append(f.versions, args[0].Value.(uint64))
+
+	case query == `delete from "schema_migrations" where "version" = $1`:
+		f.versions = f.versions[:len(f.versions)-1]
+
+	default:
+		if strings.Contains(query, "fail") {
+			return sqlfake.Result{}, errors.New("migration failed")
+		}
+
+		f.executed = append(f.executed, query)
+	}
+
+	return sqlfake.Result{RowsAffected: 1}, nil
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_email.up.sql":      {Data: []byte("alter table users add column email text")},
+		"0001_create_users.up.sql":   {Data: []byte("create table users (id int)")},
+		"0001_create_users.down.sql": {Data: []byte("drop table users")},
+		"README.md":                  {Data: []byte("ignored")},
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expected := []Migration{
+		{Version: 1, Name: "create_users", Up: "create table users (id int)", Down: "drop table users"},
+		{Version: 2, Name: "add_email", Up: "alter table users add column email text"},
+	}
+
+	if len(migrations) != len(expected) {
+		t.Fatalf("unexpected migrations %+v", migrations)
+	}
+
+	for i := range expected {
+		if migrations[i] != expected[i] {
+			t.Logf("Expecting: %+v", expected[i])
+			t.Logf("Actual: %+v", migrations[i])
+			t.Fatalf("unexpected migration %d", i)
+		}
+	}
+
+	type test struct {
+		fsys     fstest.MapFS
+		expected error
+	}
+
+	tests := []test{
+		{fsys: fstest.MapFS{"create_users.up.sql": {}}, expected: ErrBadFilename},
+		{fsys: fstest.MapFS{"0001_users.sql": {}}, expected: ErrBadFilename},
+		{fsys: fstest.MapFS{"0001_a.up.sql": {Data: []byte("x")}, "0001_b.up.sql": {Data: []byte("y")}}, expected: ErrDuplicateVersion},
+		{fsys: fstest.MapFS{"0001_a.down.sql": {Data: []byte("x")}}, expected: ErrMissingUp},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if _, err := Load(test.fsys); !errors.Is(err, test.expected) {
+			t.Fatalf("[%d] expecting error %v, got %v", i, test.expected, err)
+		}
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeDB{tables: make(map[string]bool)}
+	fake, db := sqlfake.New(f.handle)
+	defer db.Close()
+
+	migrations := []Migration{
+		{Version: 2, Name: "b", Up: "up 2", Down: "down 2"},
+		{Version: 1, Name: "a", Up: "up 1", Down: "down 1"},
+		{Version: 3, Name: "c", Up: "up 3"},
+	}
+
+	m, err := New(db, sqlquery.Postgres, migrations, LockRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	applied, err := m.UpTo(ctx, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(applied) != 2 || applied[0].Version != 1 || applied[1].Version != 2 {
+		t.Fatalf("unexpected applied migrations %+v", applied)
+	}
+
+	if fake.Begins != 2 || fake.Commits != 2 {
+		t.Fatalf("unexpected tx counts: begins %d, commits %d", fake.Begins, fake.Commits)
+	}
+
+	applied, err = m.Up(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(applied) != 1 || applied[0].Version != 3 {
+		t.Fatalf("unexpected applied migrations %+v", applied)
+	}
+
+	version, err := m.Version(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if version != 3 {
+		t.Fatalf("unexpected version %d", version)
+	}
+
+	// Migration 3 has no down
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrNoDown) {
+		t.Fatalf("expecting ErrNoDown, got %v", err)
+	}
+
+	if f.locked {
+		t.Fatal("lock was not released after error")
+	}
+
+	f.versions = f.versions[:2]
+	rolledBack, err := m.Down(ctx, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if len(rolledBack) != 2 || rolledBack[0].Version != 2 || rolledBack[1].Version != 1 {
+		t.Fatalf("unexpected rolled back migrations %+v", rolledBack)
+	}
+
+	expectedExecuted := []string{"up 1", "up 2", "up 3", "down 2", "down 1"}
+	if strings.Join(f.executed, ",") != strings.Join(expectedExecuted, ",") {
+		t.Fatalf("unexpected executed migrations %v", f.executed)
+	}
+
+	// Lock held by another migrator
+	f.locked = true
+	if _, err := m.Up(ctx); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expecting ErrLocked, got %v", err)
+	}
+
+	if err := m.Unlock(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if f.locked {
+		t.Fatal("lock was not released by Unlock")
+	}
+}
+
+func TestMigratorErrors(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeDB{tables: make(map[string]bool)}
+	fake, db := sqlfake.New(f.handle)
+	defer db.Close()
+
+	m, err := New(db, sqlquery.Postgres, []Migration{
+		{Version: 1, Name: "a", Up: "up 1"},
+		{Version: 2, Name: "b", Up: "fail"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	applied, err := m.Up(ctx)
+	if err == nil {
+		t.Fatal("expecting error from failed migration")
+	}
+
+	if len(applied) != 1 || fake.Rollbacks != 1 {
+		t.Fatalf("unexpected applied %+v and rollbacks %d", applied, fake.Rollbacks)
+	}
+
+	// Out of order
+	f.versions = []uint64{2}
+	if _, err := m.Up(ctx); !errors.Is(err, ErrOutOfOrder) {
+		t.Fatalf("expecting ErrOutOfOrder, got %v", err)
+	}
+
+	// Applied version without migration
+	f.versions = []uint64{1, 2, 3}
+	if _, err := m.Version(ctx); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("expecting ErrUnknownVersion, got %v", err)
+	}
+
+	if _, err := New(db, sqlquery.Postgres, []Migration{{Version: 1}, {Version: 1}}); !errors.Is(err, ErrDuplicateVersion) {
+		t.Fatalf("expecting ErrDuplicateVersion, got %v", err)
+	}
+
+	if _, err := New(db, sqlquery.Postgres, nil, Table("migrations; drop table users")); !errors.Is(err, sqlquery.ErrInvalidIdentifier) {
+		t.Fatalf("expecting ErrInvalidIdentifier, got %v", err)
+	}
+}
@@ -0,0 +1,320 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	TagKey = "db"
+
+	tagOptOmitEmpty  = "omitempty"
+	tagOptReadOnly   = "readonly"
+	tagOptPrimaryKey = "pk"
+)
+
+var (
+	ErrNotStruct    = errors.New("value is not a struct or pointer to struct")
+	ErrNoTableName  = errors.New("missing table name")
+	ErrNoColumnTags = errors.New("struct has no db-tagged fields")
+)
+
+// Tag represents a parsed `db` struct tag, e.g. `db:"id,pk,readonly"`.
+type Tag struct {
+	Column string
+
+	// OmitEmpty omits the column from create and update columns
+	// if the field has zero value.
+	OmitEmpty bool
+
+	// ReadOnly columns are never written, e.g. DB-generated columns.
+	ReadOnly bool
+
+	// PrimaryKey columns are used in Where, and are never updated.
+	PrimaryKey bool
+}
+
+// ParseTag parses tag value |s|. It returns false if |s| is empty or "-",
+// or if |s| has no column name.
+func ParseTag(s string) (Tag, bool) {
+	if s == "" || s == "-" {
+		return Tag{}, false
+	}
+
+	parts := strings.Split(s, ",")
+	tag := Tag{Column: strings.TrimSpace(parts[0])}
+	if tag.Column == "" {
+		return Tag{}, false
+	}
+
+	for _, opt := range parts[1:] {
+		switch strings.TrimSpace(opt) {
+		case tagOptOmitEmpty:
+			tag.OmitEmpty = true
+		case tagOptReadOnly:
+			tag.ReadOnly = true
+		case tagOptPrimaryKey:
+			tag.PrimaryKey = true
+		}
+	}
+
+	return tag, true
+}
+
+type structField struct {
+	Tag
+
+	index []int
+}
+
+// structInfo is the cached column mapping of a struct type.
+type structInfo struct {
+	fields   []structField
+	byColumn map[string]int
+}
+
+// structInfos caches *structInfo by reflect.Type
+var structInfos sync.Map
+
+func structInfoOf(t reflect.Type) (*structInfo, error) {
+	if cached, ok := structInfos.Load(t); ok {
+		return cached.(*structInfo), nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: got %s", ErrNotStruct, t.String())
+	}
+
+	info := &structInfo{byColumn: make(map[string]int)}
+	if err := collectFields(info, t, nil); err != nil {
+		return nil, err
+	}
+
+	if len(info.fields) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoColumnTags, t.String())
+	}
+
+	cached, _ := structInfos.LoadOrStore(t, info)
+	return cached.(*structInfo), nil
+}
+
+// collectFields walks struct type |t| and collects tagged fields into |info|.
+// Untagged embedded structs are flattened into the parent.
+func collectFields(info *structInfo, t reflect.Type, parent []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		index := append(append([]int{}, parent...), i)
+
+		tagValue, hasTag := field.Tag.Lookup(TagKey)
+		if field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			if err := collectFields(info, field.Type, index); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := ParseTag(tagValue)
+		if !ok {
+			continue
+		}
+
+		if _, dup := info.byColumn[tag.Column]; dup {
+			return fmt.Errorf("duplicate column %s in struct %s", tag.Column, t.String())
+		}
+
+		info.byColumn[tag.Column] = len(info.fields)
+		info.fields = append(info.fields, structField{Tag: tag, index: index})
+	}
+
+	return nil
+}
+
+// StructModel wraps a struct with `db` tags, and implements
+// ModelCreate, ModelUpdate and ModelWhere using the tags.
+//
+// Columns are mapped from exported fields with `db` tags.
+// Fields without tags (or with `db:"-"`) are ignored,
+// while untagged embedded structs are flattened.
+type StructModel struct {
+	table string
+	value reflect.Value
+	info  *structInfo
+}
+
+// NewStructModel returns *StructModel wrapping |v|, which must be a struct
+// or a pointer to struct. If |table| is empty, |v| must implement ModelBase.
+//
+// If |v| is a pointer, the returned model reads from the pointed struct,
+// so changes to the struct are reflected in the model,
+// and returned columns can be scanned back into it (see ModelReturning).
+func NewStructModel(table string, v interface{}) (*StructModel, error) {
+	if table == "" {
+		base, ok := v.(ModelBase)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T does not implement ModelBase", ErrNoTableName, v)
+		}
+
+		table = base.TableName()
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, fmt.Errorf("%w: got nil %s", ErrNotStruct, value.Type().String())
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: got %T", ErrNotStruct, v)
+	}
+
+	info, err := structInfoOf(value.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	return &StructModel{
+		table: table,
+		value: value,
+		info:  info,
+	}, nil
+}
+
+// StructModels wraps each of |items| with NewStructModel,
+// so that the result can be used with InsertAll.
+func StructModels[T any](table string, items ...T) ([]ModelCreate, error) {
+	models := make([]ModelCreate, len(items))
+	for i := range items {
+		model, err := NewStructModel(table, items[i])
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		models[i] = model
+	}
+
+	return models, nil
+}
+
+func (m *StructModel) TableName() string {
+	return m.table
+}
+
+func (m *StructModel) ColumnsCreate() []string {
+	columns, _ := m.collect(m.isCreate)
+	return columns
+}
+
+func (m *StructModel) ValuesCreate() []interface{} {
+	_, values := m.collect(m.isCreate)
+	return values
+}
+
+func (m *StructModel) MapColumnValuesCreate() map[string]interface{} {
+	return MapColumnValues(m.collect(m.isCreate))
+}
+
+func (m *StructModel) ColumnsUpdate() []string {
+	columns, _ := m.collect(m.isUpdate)
+	return columns
+}
+
+func (m *StructModel) ValuesUpdate() []interface{} {
+	_, values := m.collect(m.isUpdate)
+	return values
+}
+
+func (m *StructModel) MapColumnValuesUpdate() map[string]interface{} {
+	return MapColumnValues(m.collect(m.isUpdate))
+}
+
+// Where returns primary key columns and their values
+func (m *StructModel) Where() map[string]interface{} {
+	return MapColumnValues(m.collect(func(f *structField, _ reflect.Value) bool {
+		return f.PrimaryKey
+	}))
+}
+
+// ReturningDests returns pointers to fields mapped to |columns|,
+// so that returned columns can be scanned back into the struct.
+// The model must wrap a pointer to struct.
+func (m *StructModel) ReturningDests(columns []string) ([]interface{}, error) {
+	if !m.value.CanAddr() {
+		return nil, fmt.Errorf("%w: %s is not a pointer", ErrNotModelReturning, m.value.Type().String())
+	}
+
+	dests := make([]interface{}, len(columns))
+	for i, column := range columns {
+		j, ok := m.info.byColumn[column]
+		if !ok {
+			return nil, fmt.Errorf("no field for column %s in %s", column, m.value.Type().String())
+		}
+
+		dests[i] = m.value.FieldByIndex(m.info.fields[j].index).Addr().Interface()
+	}
+
+	return dests, nil
+}
+
+func (m *StructModel) isCreate(f *structField, v reflect.Value) bool {
+	return !f.ReadOnly && !(f.OmitEmpty && v.IsZero())
+}
+
+func (m *StructModel) isUpdate(f *structField, v reflect.Value) bool {
+	return !f.ReadOnly && !f.PrimaryKey && !(f.OmitEmpty && v.IsZero())
+}
+
+func (m *StructModel) collect(
+	filter func(*structField, reflect.Value) bool,
+) (
+	[]string,
+	[]interface{},
+) {
+	var columns []string     //nolint:prealloc
+	var values []interface{} //nolint:prealloc
+
+	for i := range m.info.fields {
+		field := &m.info.fields[i]
+		value := m.value.FieldByIndex(field.index)
+
+		if !filter(field, value) {
+			continue
+		}
+
+		columns = append(columns, field.Column)
+		values = append(values, value.Interface())
+	}
+
+	return columns, values
+}
+
+// MapColumnValues zips |columns| and |values| into a map.
+// It is used by StructModel and code generated by sqlquerygen.
+func MapColumnValues(columns []string, values []interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, len(columns))
+	for i := range columns {
+		m[columns[i]] = values[i]
+	}
+
+	return m
+}
+
+// IsZero reports whether |v| is nil or zero value of its type.
+// It is used to check `omitempty` columns.
+func IsZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	return reflect.ValueOf(v).IsZero()
+}
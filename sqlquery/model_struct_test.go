@@ -0,0 +1,173 @@
+package sqlquery
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Audit struct {
+	CreatedBy string `db:"CREATED_BY"`
+}
+
+type user struct {
+	Audit
+
+	ID       uint64 `db:"ID,pk,readonly"`
+	Name     string `db:"NAME"`
+	Nickname string `db:"NICKNAME,omitempty"`
+	Ignored  string `db:"-"`
+	NoTag    string
+	internal string `db:"INTERNAL"` //nolint:unused
+}
+
+func TestParseTag(t *testing.T) {
+	type test struct {
+		tag      string
+		ok       bool
+		expected Tag
+	}
+
+	tests := []test{
+		{tag: "", ok: false},
+		{tag: "-", ok: false},
+		{tag: ",omitempty", ok: false},
+		{tag: "ID", ok: true, expected: Tag{Column: "ID"}},
+		{tag: "ID,pk,readonly", ok: true, expected: Tag{Column: "ID", PrimaryKey: true, ReadOnly: true}},
+		{tag: "NAME, omitempty", ok: true, expected: Tag{Column: "NAME", OmitEmpty: true}},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		tag, ok := ParseTag(test.tag)
+		if ok != test.ok {
+			t.Fatalf("unexpected ok for tag \"%s\": expecting %v, got %v", test.tag, test.ok, ok)
+		}
+
+		if tag != test.expected {
+			t.Fatalf("unexpected tag for \"%s\": expecting %+v, got %+v", test.tag, test.expected, tag)
+		}
+	}
+}
+
+func TestStructModel(t *testing.T) {
+	u := &user{
+		Audit: Audit{CreatedBy: "admin"},
+		ID:    69,
+		Name:  "soy",
+	}
+
+	model, err := NewStructModel("USERS", u)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if table := model.TableName(); table != "USERS" {
+		t.Fatalf("unexpected table name %s", table)
+	}
+
+	assertEqual(t, "ColumnsCreate", []string{"CREATED_BY", "NAME"}, model.ColumnsCreate())
+	assertEqual(t, "ValuesCreate", []interface{}{"admin", "soy"}, model.ValuesCreate())
+	assertEqual(t, "ColumnsUpdate", []string{"CREATED_BY", "NAME"}, model.ColumnsUpdate())
+	assertEqual(t, "Where", map[string]interface{}{"ID": uint64(69)}, model.Where())
+
+	// Model reads from pointer, so omitempty column should now show up
+	u.Nickname = "dev"
+
+	assertEqual(t, "ColumnsCreate", []string{"CREATED_BY", "NAME", "NICKNAME"}, model.ColumnsCreate())
+	assertEqual(
+		t, "MapColumnValuesUpdate",
+		map[string]interface{}{"CREATED_BY": "admin", "NAME": "soy", "NICKNAME": "dev"},
+		model.MapColumnValuesUpdate(),
+	)
+}
+
+func TestStructModelErrors(t *testing.T) {
+	if _, err := NewStructModel("", &user{}); !errors.Is(err, ErrNoTableName) {
+		t.Fatalf("expecting ErrNoTableName, got %v", err)
+	}
+
+	if _, err := NewStructModel("FOO", 1); !errors.Is(err, ErrNotStruct) {
+		t.Fatalf("expecting ErrNotStruct, got %v", err)
+	}
+
+	if _, err := NewStructModel("FOO", (*user)(nil)); !errors.Is(err, ErrNotStruct) {
+		t.Fatalf("expecting ErrNotStruct, got %v", err)
+	}
+
+	if _, err := NewStructModel("FOO", struct{ A int }{A: 1}); !errors.Is(err, ErrNoColumnTags) {
+		t.Fatalf("expecting ErrNoColumnTags, got %v", err)
+	}
+
+	type dup struct {
+		A int `db:"A"`
+		B int `db:"A"`
+	}
+
+	if _, err := NewStructModel("FOO", dup{}); err == nil {
+		t.Fatal("expecting error from duplicate columns")
+	}
+}
+
+func TestInsertAllStructs(t *testing.T) {
+	users := []user{
+		{ID: 1, Name: "a", Audit: Audit{CreatedBy: "x"}},
+		{ID: 2, Name: "b", Audit: Audit{CreatedBy: "y"}},
+	}
+
+	items, err := StructModels("USERS", users...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	query, values, err := InsertAll(Dollar, items...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	expectedQuery := "insert all into USERS (CREATED_BY,NAME) values ($1,$2) ($3,$4)"
+	if query != expectedQuery {
+		t.Logf("Expecting:\n\"%s\"", expectedQuery)
+		t.Logf("Actual:\n\"%s\"", query)
+
+		t.Fatalf("unexpected query")
+	}
+
+	assertEqual(t, "values", []interface{}{"x", "a", "y", "b"}, values)
+
+	// Mismatched omitempty columns should fail
+	users[1].Nickname = "bad"
+	items, err = StructModels("USERS", users...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+
+	if _, _, err := InsertAll(Dollar, items...); err == nil {
+		t.Fatal("expecting error from mismatched columns")
+	}
+}
+
+func BenchmarkStructModel(b *testing.B) {
+	u := &user{ID: 1, Name: "soy", Nickname: "dev"}
+
+	for i := 0; i < b.N; i++ {
+		model, err := NewStructModel("USERS", u)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_ = model.ValuesCreate()
+	}
+}
+
+func assertEqual(t *testing.T, what string, expected, actual interface{}) {
+	t.Helper()
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Logf("Expecting: %#v", expected)
+		t.Logf("Actual: %#v", actual)
+
+		t.Fatalf("unexpected %s", what)
+	}
+}
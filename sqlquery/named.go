@@ -0,0 +1,272 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrMissingParam = errors.New("missing named parameter")
+	ErrUnusedParam  = errors.New("unused named parameter")
+	ErrBadParams    = errors.New("params must be a map with string keys or a struct")
+)
+
+// Named rewrites named parameters in |query|, e.g. ":user_id", to |placeholder|,
+// and returns the query with its args ordered from |params|.
+//
+// |params| is a map with string keys (e.g. map[string]interface{}),
+// or a struct (or pointer to struct) whose `db` tags are used as parameter names.
+// It returns ErrMissingParam if a parameter is not in |params|, and ErrUnusedParam
+// if a map key is not used in |query|. Struct fields may be left unused,
+// so that models can be passed as is.
+//
+// Named parameters inside quoted strings and identifiers, dollar-quoted strings
+// and comments are left untouched, as well as "::" casts (e.g. "created_at::date")
+// and PL/SQL assignments (":=").
+//
+// With numbered placeholders (Dollar, Colon and AtP), a parameter used more than
+// once is bound to the same number and appears only once in args.
+// With QuestionMark, the value is repeated for every occurrence.
+func Named(query string, placeholder Placeholder, params interface{}) (string, []interface{}, error) {
+	if !placeholder.IsValid() {
+		return "", nil, fmt.Errorf("bad placeholder %d", placeholder)
+	}
+
+	lookup, names, err := namedLookup(params)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var out strings.Builder
+	var args []interface{}
+
+	numbers := make(map[string]uint)
+	used := make(map[string]bool)
+
+	err = scanNamed(query, func(text string, name string) error {
+		if name == "" {
+			out.WriteString(text)
+			return nil
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+
+		used[name] = true
+
+		if placeholder == QuestionMark {
+			args = append(args, value)
+			out.WriteString("?")
+
+			return nil
+		}
+
+		n, ok := numbers[name]
+		if !ok {
+			args = append(args, value)
+			n = uint(len(args))
+			numbers[name] = n
+		}
+
+		out.WriteString(placeholder.Bind(n))
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var unused []string
+	for _, name := range names {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+
+	if len(unused) != 0 {
+		sort.Strings(unused)
+		return "", nil, fmt.Errorf("%w: %s", ErrUnusedParam, strings.Join(unused, ", "))
+	}
+
+	return out.String(), args, nil
+}
+
+// RawNamed returns a Builder that calls Named with |query|, |placeholder| and |params|.
+func RawNamed(query string, placeholder Placeholder, params interface{}) Builder {
+	return BuilderFunc(func() (string, []interface{}, error) {
+		return Named(query, placeholder, params)
+	})
+}
+
+// namedLookup returns lookup function for |params|, and names
+// that must all be used (i.e. map keys)
+func namedLookup(params interface{}) (func(string) (interface{}, bool), []string, error) {
+	if params == nil {
+		return func(string) (interface{}, bool) { return nil, false }, nil, nil
+	}
+
+	if m, ok := params.(map[string]interface{}); ok {
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+
+		return func(name string) (interface{}, bool) {
+			v, ok := m[name]
+			return v, ok
+		}, names, nil
+	}
+
+	value := reflect.ValueOf(params)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, nil, fmt.Errorf("%w: got nil %T", ErrBadParams, params)
+		}
+
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, nil, fmt.Errorf("%w: got %T", ErrBadParams, params)
+		}
+
+		names := make([]string, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			names = append(names, iter.Key().String())
+		}
+
+		return func(name string) (interface{}, bool) {
+			v := value.MapIndex(reflect.ValueOf(name).Convert(value.Type().Key()))
+			if !v.IsValid() {
+				return nil, false
+			}
+
+			return v.Interface(), true
+		}, names, nil
+
+	case reflect.Struct:
+		info, err := structInfoOf(value.Type())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return func(name string) (interface{}, bool) {
+			i, ok := info.byColumn[name]
+			if !ok {
+				return nil, false
+			}
+
+			return value.FieldByIndex(info.fields[i].index).Interface(), true
+		}, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: got %T", ErrBadParams, params)
+}
+
+// scanNamed splits |query| into text and named parameters,
+// calling |f| with either text or a parameter name
+func scanNamed(query string, f func(text string, name string) error) error {
+	start := 0
+	flush := func(end int) error {
+		if end > start {
+			if err := f(query[start:end], ""); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for i := 0; i < len(query); {
+		if skip := skipQuoted(query, i); skip > i {
+			i = skip
+			continue
+		}
+
+		c := query[i]
+
+		switch {
+		case c == '$' && (i == 0 || !isNameByte(query[i-1], false) && query[i-1] != '$'):
+			// Postgres dollar-quoted strings, e.g. $$foo$$ or $tag$foo$tag$,
+			// but not "$" inside identifiers, e.g. Oracle's "v$session"
+			tag, ok := dollarTag(query[i:])
+			if !ok {
+				i++
+				break
+			}
+
+			end := strings.Index(query[i+len(tag):], tag)
+			if end == -1 {
+				return flush(len(query))
+			}
+
+			i += len(tag) + end + len(tag)
+
+		case c == ':':
+			// Casts "::type"
+			if i+1 < len(query) && query[i+1] == ':' {
+				i += 2
+				break
+			}
+
+			end := i + 1
+			for end < len(query) && isNameByte(query[end], end == i+1) {
+				end++
+			}
+
+			if end == i+1 {
+				i++
+				break
+			}
+
+			if err := flush(i); err != nil {
+				return err
+			}
+
+			if err := f("", query[i+1:end]); err != nil {
+				return err
+			}
+
+			i, start = end, end
+
+		default:
+			i++
+		}
+	}
+
+	return flush(len(query))
+}
+
+func isNameByte(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case !first && c >= '0' && c <= '9':
+		return true
+	}
+
+	return false
+}
+
+// dollarTag returns the opening tag of dollar-quoted string at the start of |s|
+func dollarTag(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1], true
+		}
+
+		if !isNameByte(s[i], i == 1) {
+			return "", false
+		}
+	}
+
+	return "", false
+}
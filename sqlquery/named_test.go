@@ -0,0 +1,117 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	type params struct {
+		UserID uint64 `db:"user_id"`
+		Status string `db:"status"`
+		Unused string `db:"unused"`
+	}
+
+	type test struct {
+		query          string
+		placeholder    Placeholder
+		params         interface{}
+		expectedQuery  string
+		expectedValues []interface{}
+	}
+
+	tests := []test{
+		{
+			query:          "select * from posts where user_id = :user_id and (author_id = :user_id or status = :status)",
+			placeholder:    Dollar,
+			params:         map[string]interface{}{"user_id": 1, "status": "published"},
+			expectedQuery:  "select * from posts where user_id = $1 and (author_id = $1 or status = $2)",
+			expectedValues: []interface{}{1, "published"},
+		},
+		{
+			query:          "select * from posts where user_id = :user_id and (author_id = :user_id or status = :status)",
+			placeholder:    QuestionMark,
+			params:         &params{UserID: 1, Status: "published"},
+			expectedQuery:  "select * from posts where user_id = ? and (author_id = ? or status = ?)",
+			expectedValues: []interface{}{uint64(1), uint64(1), "published"},
+		},
+		{
+			query:          "update users set status = :status where id = :user_id",
+			placeholder:    Colon,
+			params:         params{UserID: 2, Status: "x"},
+			expectedQuery:  "update users set status = :1 where id = :2",
+			expectedValues: []interface{}{"x", uint64(2)},
+		},
+		{
+			query:          "select * from t where a = :a",
+			placeholder:    AtP,
+			params:         map[string]string{"a": "b"},
+			expectedQuery:  "select * from t where a = @p1",
+			expectedValues: []interface{}{"b"},
+		},
+		{
+			// Literals, identifiers, comments, casts and dollar-quoted strings
+			query: "select ':no', \":no\", `:no`, 'it''s :no', created_at::date -- :no\n" +
+				"/* :no */ from v$session where x = $tag$ :no $tag$ and y = :a and z = $$:no$$ and w = $1",
+			placeholder: Dollar,
+			params:      map[string]interface{}{"a": 1},
+			expectedQuery: "select ':no', \":no\", `:no`, 'it''s :no', created_at::date -- :no\n" +
+				"/* :no */ from v$session where x = $tag$ :no $tag$ and y = $1 and z = $$:no$$ and w = $1",
+			expectedValues: []interface{}{1},
+		},
+		{
+			query:         "begin x := 1; end; -- unterminated :no",
+			placeholder:   Colon,
+			expectedQuery: "begin x := 1; end; -- unterminated :no",
+		},
+		{
+			query:         "select 'unterminated :no",
+			placeholder:   Dollar,
+			expectedQuery: "select 'unterminated :no",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		query, values, err := Named(test.query, test.placeholder, test.params)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if query != test.expectedQuery {
+			t.Logf("Expecting:\n\"%s\"", test.expectedQuery)
+			t.Logf("Actual:\n\"%s\"", query)
+
+			t.Fatalf("[%d] unexpected query", i)
+		}
+
+		assertEqual(t, "values", test.expectedValues, values)
+	}
+}
+
+func TestNamedErrors(t *testing.T) {
+	type test struct {
+		query    string
+		params   interface{}
+		expected error
+	}
+
+	tests := []test{
+		{query: "a = :a and b = :b", params: map[string]interface{}{"a": 1}, expected: ErrMissingParam},
+		{query: "a = :a", params: map[string]interface{}{"a": 1, "b": 2}, expected: ErrUnusedParam},
+		{query: "a = :a", params: nil, expected: ErrMissingParam},
+		{query: "a = :a", params: map[int]interface{}{1: 1}, expected: ErrBadParams},
+		{query: "a = :a", params: []int{1}, expected: ErrBadParams},
+		{query: "a = :a", params: (*user)(nil), expected: ErrBadParams},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		_, _, err := Named(test.query, Dollar, test.params)
+		if !errors.Is(err, test.expected) {
+			t.Fatalf("[%d] expecting error %v, got %v", i, test.expected, err)
+		}
+	}
+}
@@ -5,18 +5,31 @@ import (
 	"fmt"
 )
 
+// InsertAllOracle returns query and bind values for Oracle's multi-table INSERT ALL.
+//
+// Table and column names are validated with ValidateIdentifier, but not quoted,
+// because quoted names are case-sensitive in Oracle.
+// Use InsertBuilder for dialect-aware quoting.
 func InsertAllOracle(items ...ModelCreate) (string, []interface{}, error) {
 	if len(items) == 0 {
 		return "", nil, errors.New("empty items slice")
 	}
 
-	tableName := items[0].TableName()
-	cols := items[0].ColumnsCreate()
-	lenCols := len(cols)
+	if err := checkItemsCreate(items); err != nil {
+		return "", nil, err
+	}
+
+	query, values := insertAllOracle(items[0].TableName(), ClauseColumns(items[0].ColumnsCreate()), items)
+	return query, values, nil
+}
+
+// insertAllOracle returns INSERT ALL query and bind values for |items|,
+// using |tableName| and |clauseColumns| as is.
+func insertAllOracle(tableName, clauseColumns string, items []ModelCreate) (string, []interface{}) {
+	lenCols := len(items[0].ColumnsCreate())
 
 	var valuesAll []interface{}
 	query := "insert all"
-	clauseColumns := ClauseColumns(cols)
 
 	bindPointer := 1
 	for i := range items {
@@ -33,5 +46,5 @@ func InsertAllOracle(items ...ModelCreate) (string, []interface{}, error) {
 	// https://stackoverflow.com/questions/73751/what-is-the-dual-table-in-oracle
 	query += " select * from dual"
 
-	return query, valuesAll, nil
+	return query, valuesAll
 }
@@ -0,0 +1,613 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrDDLUnsupported = errors.New("ddl is not supported in this dialect")
+	ErrBadColumn      = errors.New("bad column definition")
+	ErrNoColumns      = errors.New("table has no columns")
+)
+
+// ColumnType is an abstract column type, which is mapped to
+// a concrete type for each Dialect.
+type ColumnType uint8
+
+const (
+	TypeInt ColumnType = iota + 1
+	TypeBigInt
+	TypeBool
+	TypeFloat
+	TypeDecimal // Uses Column.Size as precision, and Column.Scale as scale
+	TypeVarchar // Uses Column.Size as length
+	TypeText
+	TypeBytes
+	TypeDate
+	TypeTimestamp // Timestamp with time zone where supported
+	TypeJSON
+	TypeUUID
+)
+
+func (t ColumnType) IsValid() bool {
+	return t >= TypeInt && t <= TypeUUID
+}
+
+// ReferentialAction is action for foreign keys' on delete and on update.
+type ReferentialAction uint8
+
+const (
+	NoAction ReferentialAction = iota
+	Cascade
+	SetNull
+	Restrict
+)
+
+func (a ReferentialAction) String() string {
+	switch a {
+	case Cascade:
+		return "cascade"
+	case SetNull:
+		return "set null"
+	case Restrict:
+		return "restrict"
+	}
+
+	return "no action"
+}
+
+// Column describes a table column.
+// Default is raw SQL expression, e.g. "0", "'foo'" or "current_timestamp",
+// and must never contain user input.
+type Column struct {
+	Name          string
+	Type          ColumnType
+	Size          uint
+	Scale         uint
+	Nullable      bool
+	Default       string
+	AutoIncrement bool
+}
+
+// Index describes a table index. If Name is empty, it is derived from
+// table and column names, e.g. "ix_users_name".
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKey describes a foreign key constraint. Name may be empty,
+// in which case the database names the constraint.
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   ReferentialAction
+	OnUpdate   ReferentialAction
+}
+
+// Table describes a table schema, which can be rendered to DDL with CreateTable.
+type Table struct {
+	Name        string
+	Columns     []Column
+	PrimaryKey  []string
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// CreateTable returns DDL statements for creating |table| in |dialect|:
+// the create table statement, followed by create index statements.
+//
+// Auto-increment columns use identity columns (Postgres, Oracle and SQL Server),
+// auto_increment (MySQL), or "integer primary key autoincrement" (SQLite),
+// in which case the column must be the only primary key column.
+func CreateTable(dialect Dialect, table Table) ([]string, error) {
+	if !dialect.IsValid() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidDialect, dialect)
+	}
+
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoColumns, table.Name)
+	}
+
+	name, err := dialect.QuoteIdentifier(table.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := make([]string, 0, len(table.Columns)+1+len(table.ForeignKeys))
+	inlinePK := false
+
+	for i := range table.Columns {
+		column := &table.Columns[i]
+
+		def, err := dialect.columnDefinition(column)
+		if err != nil {
+			return nil, err
+		}
+
+		// SQLite only supports autoincrement on inline integer primary key
+		if dialect == SQLite && column.AutoIncrement {
+			if len(table.PrimaryKey) != 1 || table.PrimaryKey[0] != column.Name {
+				return nil, fmt.Errorf("%w: sqlite auto-increment column %s must be the only primary key column", ErrBadColumn, column.Name)
+			}
+
+			inlinePK = true
+		}
+
+		defs = append(defs, def)
+	}
+
+	if len(table.PrimaryKey) != 0 && !inlinePK {
+		pk, err := ClauseColumnsQuoted(dialect, table.PrimaryKey)
+		if err != nil {
+			return nil, err
+		}
+
+		defs = append(defs, "primary key "+pk)
+	}
+
+	for i := range table.ForeignKeys {
+		fk, err := dialect.foreignKey(&table.ForeignKeys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		defs = append(defs, fk)
+	}
+
+	stmts := []string{fmt.Sprintf("create table %s (%s)", name, strings.Join(defs, ", "))}
+
+	for i := range table.Indexes {
+		stmt, err := dialect.createIndex(table.Name, &table.Indexes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, nil
+}
+
+// DropTable returns DDL statement for dropping |table|.
+func DropTable(dialect Dialect, table string) (string, error) {
+	if !dialect.IsValid() {
+		return "", fmt.Errorf("%w: %d", ErrInvalidDialect, dialect)
+	}
+
+	name, err := dialect.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	return "drop table " + name, nil
+}
+
+// Alteration is a change to an existing table, used with AlterTable.
+type Alteration interface {
+	alter(dialect Dialect, table string) (string, error)
+}
+
+type (
+	AddColumn      Column
+	DropColumn     string
+	AddIndex       Index
+	DropIndex      string
+	AddForeignKey  ForeignKey
+	DropForeignKey string // Foreign key constraint name
+)
+
+// RenameColumn renames column From to To
+type RenameColumn struct {
+	From string
+	To   string
+}
+
+// AlterTable returns DDL statements for applying |alterations| to |table|,
+// one statement per alteration.
+//
+// SQLite does not support adding or dropping foreign keys on existing tables,
+// and returns ErrDDLUnsupported.
+func AlterTable(dialect Dialect, table string, alterations ...Alteration) ([]string, error) {
+	if !dialect.IsValid() {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidDialect, dialect)
+	}
+
+	stmts := make([]string, len(alterations))
+	for i, alteration := range alterations {
+		stmt, err := alteration.alter(dialect, table)
+		if err != nil {
+			return nil, err
+		}
+
+		stmts[i] = stmt
+	}
+
+	return stmts, nil
+}
+
+func (a AddColumn) alter(dialect Dialect, table string) (string, error) {
+	column := Column(a)
+	if column.AutoIncrement && dialect == SQLite {
+		return "", fmt.Errorf("%w: sqlite cannot add auto-increment column %s", ErrDDLUnsupported, column.Name)
+	}
+
+	def, err := dialect.columnDefinition(&column)
+	if err != nil {
+		return "", err
+	}
+
+	add := "add column "
+	if dialect == Oracle || dialect == SQLServer {
+		add = "add "
+	}
+
+	return dialect.alterTable(table, add+def)
+}
+
+func (a DropColumn) alter(dialect Dialect, table string) (string, error) {
+	column, err := dialect.QuoteIdentifier(string(a))
+	if err != nil {
+		return "", err
+	}
+
+	return dialect.alterTable(table, "drop column "+column)
+}
+
+func (a RenameColumn) alter(dialect Dialect, table string) (string, error) {
+	from, err := dialect.QuoteIdentifier(a.From)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := dialect.QuoteIdentifier(a.To)
+	if err != nil {
+		return "", err
+	}
+
+	if dialect == SQLServer {
+		// sp_rename takes names as strings, which are validated above
+		if err := ValidateIdentifier(table); err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("exec sp_rename '%s.%s', '%s', 'COLUMN'", table, a.From, a.To), nil
+	}
+
+	return dialect.alterTable(table, fmt.Sprintf("rename column %s to %s", from, to))
+}
+
+func (a AddIndex) alter(dialect Dialect, table string) (string, error) {
+	index := Index(a)
+	return dialect.createIndex(table, &index)
+}
+
+func (a DropIndex) alter(dialect Dialect, table string) (string, error) {
+	index, err := dialect.QuoteIdentifier(string(a))
+	if err != nil {
+		return "", err
+	}
+
+	switch dialect {
+	case MySQL, SQLServer:
+		name, err := dialect.QuoteIdentifier(table)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("drop index %s on %s", index, name), nil
+	}
+
+	return "drop index " + index, nil
+}
+
+func (a AddForeignKey) alter(dialect Dialect, table string) (string, error) {
+	if dialect == SQLite {
+		return "", fmt.Errorf("%w: sqlite cannot add foreign keys to existing tables", ErrDDLUnsupported)
+	}
+
+	fk := ForeignKey(a)
+	def, err := dialect.foreignKey(&fk)
+	if err != nil {
+		return "", err
+	}
+
+	return dialect.alterTable(table, "add "+def)
+}
+
+func (a DropForeignKey) alter(dialect Dialect, table string) (string, error) {
+	if dialect == SQLite {
+		return "", fmt.Errorf("%w: sqlite cannot drop foreign keys from existing tables", ErrDDLUnsupported)
+	}
+
+	name, err := dialect.QuoteIdentifier(string(a))
+	if err != nil {
+		return "", err
+	}
+
+	if dialect == MySQL {
+		return dialect.alterTable(table, "drop foreign key "+name)
+	}
+
+	return dialect.alterTable(table, "drop constraint "+name)
+}
+
+func (d Dialect) alterTable(table, action string) (string, error) {
+	name, err := d.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("alter table %s %s", name, action), nil
+}
+
+func (d Dialect) columnDefinition(column *Column) (string, error) {
+	name, err := d.QuoteIdentifier(column.Name)
+	if err != nil {
+		return "", err
+	}
+
+	typ, err := d.columnType(column)
+	if err != nil {
+		return "", err
+	}
+
+	def := name + " " + typ
+
+	if column.AutoIncrement {
+		if column.Nullable {
+			return "", fmt.Errorf("%w: auto-increment column %s cannot be nullable", ErrBadColumn, column.Name)
+		}
+
+		if column.Type != TypeInt && column.Type != TypeBigInt {
+			return "", fmt.Errorf("%w: auto-increment column %s must be integer", ErrBadColumn, column.Name)
+		}
+
+		switch d {
+		case Postgres, Oracle:
+			def += " generated by default as identity"
+		case MySQL:
+			def += " not null auto_increment"
+		case SQLite:
+			// SQLite only aliases rowid with exact type "integer"
+			return name + " integer primary key autoincrement", nil
+		case SQLServer:
+			def += " identity(1,1)"
+		}
+	}
+
+	if column.Default != "" {
+		def += " default " + column.Default
+	}
+
+	if !column.Nullable && !(column.AutoIncrement && d == MySQL) {
+		def += " not null"
+	}
+
+	return def, nil
+}
+
+func (d Dialect) columnType(column *Column) (string, error) {
+	switch column.Type {
+	case TypeInt:
+		if d == Oracle {
+			return "number(10)", nil
+		}
+
+		return "integer", nil
+
+	case TypeBigInt:
+		switch d {
+		case Oracle:
+			return "number(19)", nil
+		case SQLite:
+			return "integer", nil
+		}
+
+		return "bigint", nil
+
+	case TypeBool:
+		switch d {
+		case Postgres, MySQL:
+			return "boolean", nil
+		case SQLite:
+			return "integer", nil
+		case Oracle:
+			return "number(1)", nil
+		}
+
+		return "bit", nil
+
+	case TypeFloat:
+		switch d {
+		case Postgres:
+			return "double precision", nil
+		case MySQL:
+			return "double", nil
+		case SQLite:
+			return "real", nil
+		case Oracle:
+			return "binary_double", nil
+		}
+
+		return "float", nil
+
+	case TypeDecimal:
+		if column.Size == 0 || column.Scale > column.Size {
+			return "", fmt.Errorf("%w: bad decimal precision %d and scale %d for %s", ErrBadColumn, column.Size, column.Scale, column.Name)
+		}
+
+		typ := "numeric"
+		if d == Oracle {
+			typ = "number"
+		}
+
+		return fmt.Sprintf("%s(%d,%d)", typ, column.Size, column.Scale), nil
+
+	case TypeVarchar:
+		if column.Size == 0 {
+			return "", fmt.Errorf("%w: missing varchar size for %s", ErrBadColumn, column.Name)
+		}
+
+		switch d {
+		case Oracle:
+			return fmt.Sprintf("varchar2(%d)", column.Size), nil
+		case SQLServer:
+			return fmt.Sprintf("nvarchar(%d)", column.Size), nil
+		}
+
+		return fmt.Sprintf("varchar(%d)", column.Size), nil
+
+	case TypeText, TypeJSON:
+		switch d {
+		case Oracle:
+			return "clob", nil
+		case SQLServer:
+			return "nvarchar(max)", nil
+		case Postgres:
+			if column.Type == TypeJSON {
+				return "jsonb", nil
+			}
+		case MySQL:
+			if column.Type == TypeJSON {
+				return "json", nil
+			}
+		}
+
+		return "text", nil
+
+	case TypeBytes:
+		switch d {
+		case Postgres:
+			return "bytea", nil
+		case MySQL:
+			return "longblob", nil
+		case SQLServer:
+			return "varbinary(max)", nil
+		}
+
+		return "blob", nil
+
+	case TypeDate:
+		return "date", nil
+
+	case TypeTimestamp:
+		switch d {
+		case Postgres:
+			return "timestamptz", nil
+		case MySQL:
+			return "datetime(6)", nil
+		case SQLite:
+			return "timestamp", nil
+		case Oracle:
+			return "timestamp with time zone", nil
+		}
+
+		return "datetimeoffset", nil
+
+	case TypeUUID:
+		switch d {
+		case Postgres:
+			return "uuid", nil
+		case SQLServer:
+			return "uniqueidentifier", nil
+		}
+
+		return "char(36)", nil
+	}
+
+	return "", fmt.Errorf("%w: bad type %d for %s", ErrBadColumn, column.Type, column.Name)
+}
+
+func (d Dialect) foreignKey(fk *ForeignKey) (string, error) {
+	if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.RefColumns) {
+		return "", fmt.Errorf("%w: foreign key to %s has %d columns referencing %d columns", ErrBadColumn, fk.RefTable, len(fk.Columns), len(fk.RefColumns))
+	}
+
+	columns, err := ClauseColumnsQuoted(d, fk.Columns)
+	if err != nil {
+		return "", err
+	}
+
+	refTable, err := d.QuoteIdentifier(fk.RefTable)
+	if err != nil {
+		return "", err
+	}
+
+	refColumns, err := ClauseColumnsQuoted(d, fk.RefColumns)
+	if err != nil {
+		return "", err
+	}
+
+	var def string
+	if fk.Name != "" {
+		name, err := d.QuoteIdentifier(fk.Name)
+		if err != nil {
+			return "", err
+		}
+
+		def = "constraint " + name + " "
+	}
+
+	def += fmt.Sprintf("foreign key %s references %s %s", columns, refTable, refColumns)
+
+	if fk.OnDelete != NoAction {
+		// Oracle and SQL Server do not support restrict, which behaves like no action
+		if fk.OnDelete == Restrict && (d == Oracle || d == SQLServer) {
+			return "", fmt.Errorf("%w: %s does not support on delete restrict", ErrDDLUnsupported, d.String())
+		}
+
+		def += " on delete " + fk.OnDelete.String()
+	}
+
+	if fk.OnUpdate != NoAction {
+		// Oracle does not support on update
+		if d == Oracle || (fk.OnUpdate == Restrict && d == SQLServer) {
+			return "", fmt.Errorf("%w: %s does not support on update %s", ErrDDLUnsupported, d.String(), fk.OnUpdate.String())
+		}
+
+		def += " on update " + fk.OnUpdate.String()
+	}
+
+	return def, nil
+}
+
+func (d Dialect) createIndex(table string, index *Index) (string, error) {
+	if len(index.Columns) == 0 {
+		return "", fmt.Errorf("%w: index on %s has no columns", ErrBadColumn, table)
+	}
+
+	indexName := index.Name
+	if indexName == "" {
+		// Use only the table name without schema, as index names are not qualified
+		parts := strings.Split(table, ".")
+		indexName = "ix_" + parts[len(parts)-1] + "_" + strings.Join(index.Columns, "_")
+	}
+
+	name, err := d.QuoteIdentifier(indexName)
+	if err != nil {
+		return "", err
+	}
+
+	on, err := d.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	columns, err := ClauseColumnsQuoted(d, index.Columns)
+	if err != nil {
+		return "", err
+	}
+
+	create := "create index "
+	if index.Unique {
+		create = "create unique index "
+	}
+
+	return fmt.Sprintf("%s%s on %s %s", create, name, on, columns), nil
+}
@@ -0,0 +1,204 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+)
+
+var tablePosts = Table{
+	Name: "posts",
+	Columns: []Column{
+		{Name: "id", Type: TypeBigInt, AutoIncrement: true},
+		{Name: "user_id", Type: TypeBigInt},
+		{Name: "title", Type: TypeVarchar, Size: 200},
+		{Name: "price", Type: TypeDecimal, Size: 10, Scale: 2, Nullable: true},
+		{Name: "published", Type: TypeBool, Default: "false"},
+		{Name: "created_at", Type: TypeTimestamp},
+	},
+	PrimaryKey: []string{"id"},
+	Indexes: []Index{
+		{Columns: []string{"user_id", "created_at"}},
+		{Name: "uq_posts_title", Columns: []string{"title"}, Unique: true},
+	},
+	ForeignKeys: []ForeignKey{
+		{Name: "fk_posts_user", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}, OnDelete: Cascade},
+	},
+}
+
+func TestCreateTable(t *testing.T) {
+	type test struct {
+		dialect  Dialect
+		expected []string
+	}
+
+	tests := []test{
+		{
+			dialect: Postgres,
+			expected: []string{
+				`create table "posts" ("id" bigint generated by default as identity not null, "user_id" bigint not null,` +
+					` "title" varchar(200) not null, "price" numeric(10,2), "published" boolean default false not null,` +
+					` "created_at" timestamptz not null, primary key ("id"),` +
+					` constraint "fk_posts_user" foreign key ("user_id") references "users" ("id") on delete cascade)`,
+				`create index "ix_posts_user_id_created_at" on "posts" ("user_id","created_at")`,
+				`create unique index "uq_posts_title" on "posts" ("title")`,
+			},
+		},
+		{
+			dialect: MySQL,
+			expected: []string{
+				"create table `posts` (`id` bigint not null auto_increment, `user_id` bigint not null," +
+					" `title` varchar(200) not null, `price` numeric(10,2), `published` boolean default false not null," +
+					" `created_at` datetime(6) not null, primary key (`id`)," +
+					" constraint `fk_posts_user` foreign key (`user_id`) references `users` (`id`) on delete cascade)",
+				"create index `ix_posts_user_id_created_at` on `posts` (`user_id`,`created_at`)",
+				"create unique index `uq_posts_title` on `posts` (`title`)",
+			},
+		},
+		{
+			dialect: SQLite,
+			expected: []string{
+				`create table "posts" ("id" integer primary key autoincrement, "user_id" integer not null,` +
+					` "title" varchar(200) not null, "price" numeric(10,2), "published" integer default false not null,` +
+					` "created_at" timestamp not null,` +
+					` constraint "fk_posts_user" foreign key ("user_id") references "users" ("id") on delete cascade)`,
+				`create index "ix_posts_user_id_created_at" on "posts" ("user_id","created_at")`,
+				`create unique index "uq_posts_title" on "posts" ("title")`,
+			},
+		},
+		{
+			dialect: SQLServer,
+			expected: []string{
+				"create table [posts] ([id] bigint identity(1,1) not null, [user_id] bigint not null," +
+					" [title] nvarchar(200) not null, [price] numeric(10,2), [published] bit default false not null," +
+					" [created_at] datetimeoffset not null, primary key ([id])," +
+					" constraint [fk_posts_user] foreign key ([user_id]) references [users] ([id]) on delete cascade)",
+				"create index [ix_posts_user_id_created_at] on [posts] ([user_id],[created_at])",
+				"create unique index [uq_posts_title] on [posts] ([title])",
+			},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		stmts, err := CreateTable(test.dialect, tablePosts)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		assertEqual(t, test.dialect.String(), test.expected, stmts)
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	type test struct {
+		dialect     Dialect
+		alterations []Alteration
+		expected    []string
+	}
+
+	tests := []test{
+		{
+			dialect: Postgres,
+			alterations: []Alteration{
+				AddColumn{Name: "body", Type: TypeText, Nullable: true},
+				DropColumn("price"),
+				RenameColumn{From: "title", To: "headline"},
+				AddIndex{Columns: []string{"headline"}},
+				DropIndex("uq_posts_title"),
+				AddForeignKey{Name: "fk_editor", Columns: []string{"editor_id"}, RefTable: "users", RefColumns: []string{"id"}, OnDelete: SetNull},
+				DropForeignKey("fk_posts_user"),
+			},
+			expected: []string{
+				`alter table "posts" add column "body" text`,
+				`alter table "posts" drop column "price"`,
+				`alter table "posts" rename column "title" to "headline"`,
+				`create index "ix_posts_headline" on "posts" ("headline")`,
+				`drop index "uq_posts_title"`,
+				`alter table "posts" add constraint "fk_editor" foreign key ("editor_id") references "users" ("id") on delete set null`,
+				`alter table "posts" drop constraint "fk_posts_user"`,
+			},
+		},
+		{
+			dialect: MySQL,
+			alterations: []Alteration{
+				DropIndex("uq_posts_title"),
+				DropForeignKey("fk_posts_user"),
+			},
+			expected: []string{
+				"drop index `uq_posts_title` on `posts`",
+				"alter table `posts` drop foreign key `fk_posts_user`",
+			},
+		},
+		{
+			dialect: SQLServer,
+			alterations: []Alteration{
+				AddColumn{Name: "views", Type: TypeInt, Default: "0"},
+				RenameColumn{From: "title", To: "headline"},
+			},
+			expected: []string{
+				"alter table [posts] add [views] integer default 0 not null",
+				"exec sp_rename 'posts.title', 'headline', 'COLUMN'",
+			},
+		},
+		{
+			dialect:     Oracle,
+			alterations: []Alteration{AddColumn{Name: "body", Type: TypeText}},
+			expected:    []string{`alter table "posts" add "body" clob not null`},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		stmts, err := AlterTable(test.dialect, "posts", test.alterations...)
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		assertEqual(t, test.dialect.String(), test.expected, stmts)
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	type test struct {
+		f        func() error
+		expected error
+	}
+
+	createTable := func(dialect Dialect, table Table) func() error {
+		return func() error {
+			_, err := CreateTable(dialect, table)
+			return err
+		}
+	}
+
+	alterTable := func(dialect Dialect, alterations ...Alteration) func() error {
+		return func() error {
+			_, err := AlterTable(dialect, "posts", alterations...)
+			return err
+		}
+	}
+
+	tests := []test{
+		{f: createTable(Dialect(0), tablePosts), expected: ErrInvalidDialect},
+		{f: createTable(Postgres, Table{Name: "empty"}), expected: ErrNoColumns},
+		{f: createTable(Postgres, Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeVarchar}}}), expected: ErrBadColumn},
+		{f: createTable(Postgres, Table{Name: "t", Columns: []Column{{Name: "a"}}}), expected: ErrBadColumn},
+		{f: createTable(Postgres, Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeText, AutoIncrement: true}}}), expected: ErrBadColumn},
+		{f: createTable(SQLite, Table{Name: "t", Columns: []Column{{Name: "a", Type: TypeInt, AutoIncrement: true}}}), expected: ErrBadColumn},
+		{f: createTable(Postgres, Table{Name: "t; drop table users", Columns: []Column{{Name: "a", Type: TypeInt}}}), expected: ErrInvalidIdentifier},
+		{f: alterTable(SQLite, DropForeignKey("fk")), expected: ErrDDLUnsupported},
+		{f: alterTable(Oracle, AddForeignKey{Columns: []string{"a"}, RefTable: "t", RefColumns: []string{"b"}, OnUpdate: Cascade}), expected: ErrDDLUnsupported},
+		{f: alterTable(Postgres, AddForeignKey{Columns: []string{"a"}, RefTable: "t"}), expected: ErrBadColumn},
+		{f: alterTable(Postgres, RenameColumn{From: "a", To: "b'"}), expected: ErrInvalidIdentifier},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if err := test.f(); !errors.Is(err, test.expected) {
+			t.Fatalf("[%d] expecting error %v, got %v", i, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package sqlquery
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/soyart/gsl/data"
+)
+
+var (
+	ErrNoTable        = errors.New("missing table")
+	ErrBadBindCount   = errors.New("bind variables count does not match args")
+	ErrKeysetNoOrder  = errors.New("keyset pagination requires order by columns")
+	ErrKeysetCount    = errors.New("keyset values count does not match order by columns")
+	ErrOffsetNoOrder  = errors.New("offset requires order by clause in this dialect")
+	ErrInvalidDialect = errors.New("invalid dialect")
+)
+
+type joinKind uint8
+
+const (
+	joinInner joinKind = iota
+	joinLeft
+)
+
+func (k joinKind) String() string {
+	if k == joinLeft {
+		return "left join"
+	}
+
+	return "inner join"
+}
+
+// clause is a raw SQL fragment with question mark bind variables
+type clause struct {
+	sql  string
+	args []interface{}
+}
+
+type join struct {
+	kind  joinKind
+	table string
+	alias string
+	on    clause
+}
+
+type selectColumn struct {
+	expr string
+	raw  bool
+}
+
+type orderBy struct {
+	column string
+	order  data.SortOrder
+}
+
+// SelectBuilder builds SELECT statements for a Dialect.
+//
+// Table names, aliases and columns are identifiers, which are validated
+// and quoted for the dialect by Build (see Dialect.QuoteIdentifier).
+// Use ColumnsRaw for selecting expressions.
+//
+// Conditions (in Where, Having and join's ON) are raw SQL, and use
+// question mark "?" for bind variables, which Build rewrites to
+// the dialect's placeholder style (see Rebind).
+// Raw SQL is not validated, and must never contain user input.
+type SelectBuilder struct {
+	dialect Dialect
+	columns []selectColumn
+	table   string
+	alias   string
+	joins   []join
+	where   []clause
+	groupBy []string
+	having  []clause
+	orderBy []orderBy
+
+	limit    uint64
+	hasLimit bool
+	offset   uint64
+
+	keyset []interface{}
+}
+
+// Select returns a new *SelectBuilder for |dialect|.
+// If |columns| is empty, the statement selects "*".
+func Select(dialect Dialect, columns ...string) *SelectBuilder {
+	b := &SelectBuilder{dialect: dialect}
+	return b.Columns(columns...)
+}
+
+// Columns adds identifier columns, e.g. "id", "u.name" or "u.*".
+func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	for _, column := range columns {
+		b.columns = append(b.columns, selectColumn{expr: column})
+	}
+
+	return b
+}
+
+// ColumnsRaw adds raw SQL expressions as columns, e.g. "count(*) as n".
+// The expressions are not validated nor quoted.
+func (b *SelectBuilder) ColumnsRaw(exprs ...string) *SelectBuilder {
+	for _, expr := range exprs {
+		b.columns = append(b.columns, selectColumn{expr: expr, raw: true})
+	}
+
+	return b
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// FromAs sets the table with an alias, e.g. "from users u".
+func (b *SelectBuilder) FromAs(table, alias string) *SelectBuilder {
+	b.table, b.alias = table, alias
+	return b
+}
+
+// Join adds an inner join on condition |on|. |alias| may be empty.
+func (b *SelectBuilder) Join(table, alias, on string, args ...interface{}) *SelectBuilder {
+	return b.join(joinInner, table, alias, on, args)
+}
+
+// LeftJoin adds a left (outer) join on condition |on|. |alias| may be empty.
+func (b *SelectBuilder) LeftJoin(table, alias, on string, args ...interface{}) *SelectBuilder {
+	return b.join(joinLeft, table, alias, on, args)
+}
+
+func (b *SelectBuilder) join(kind joinKind, table, alias, on string, args []interface{}) *SelectBuilder {
+	b.joins = append(b.joins, join{
+		kind:  kind,
+		table: table,
+		alias: alias,
+		on:    clause{sql: on, args: args},
+	})
+
+	return b
+}
+
+// Where adds a condition. Multiple conditions are joined with "and".
+func (b *SelectBuilder) Where(condition string, args ...interface{}) *SelectBuilder {
+	b.where = append(b.where, clause{sql: condition, args: args})
+	return b
+}
+
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// Having adds a condition for group by. Multiple conditions are joined with "and".
+func (b *SelectBuilder) Having(condition string, args ...interface{}) *SelectBuilder {
+	b.having = append(b.having, clause{sql: condition, args: args})
+	return b
+}
+
+func (b *SelectBuilder) OrderBy(column string, order data.SortOrder) *SelectBuilder {
+	b.orderBy = append(b.orderBy, orderBy{column: column, order: order})
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit uint64) *SelectBuilder {
+	b.limit, b.hasLimit = limit, true
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset uint64) *SelectBuilder {
+	b.offset = offset
+	return b
+}
+
+// After enables keyset (seek) pagination: only rows after |values|
+// according to order by columns are selected. |values| must map 1:1
+// to columns added with OrderBy, i.e. the values of the last row seen.
+//
+// For columns a ascending and b descending, After(x, y) adds condition
+// "((a > x) or (a = x and b < y))".
+func (b *SelectBuilder) After(values ...interface{}) *SelectBuilder {
+	b.keyset = values
+	return b
+}
+
+// Build returns the query and its bind values.
+func (b *SelectBuilder) Build() (string, []interface{}, error) {
+	if !b.dialect.IsValid() {
+		return "", nil, fmt.Errorf("%w: %d", ErrInvalidDialect, b.dialect)
+	}
+
+	if b.table == "" {
+		return "", nil, ErrNoTable
+	}
+
+	var args []interface{}
+	placeholder := b.dialect.Placeholder()
+
+	// bind rewrites c and collects its args
+	bind := func(c clause) (string, error) {
+		rebound, count := Rebind(c.sql, placeholder, uint(len(args)+1))
+		if int(count) != len(c.args) {
+			return "", fmt.Errorf("%w: \"%s\" has %d bind variables, got %d args", ErrBadBindCount, c.sql, count, len(c.args))
+		}
+
+		args = append(args, c.args...)
+		return rebound, nil
+	}
+
+	columns := make([]string, len(b.columns))
+	for i, column := range b.columns {
+		if column.raw {
+			columns[i] = column.expr
+			continue
+		}
+
+		quoted, err := b.dialect.QuoteIdentifier(column.expr)
+		if err != nil {
+			return "", nil, err
+		}
+
+		columns[i] = quoted
+	}
+
+	query := "select "
+	if len(columns) == 0 {
+		query += "*"
+	} else {
+		query += strings.Join(columns, ", ")
+	}
+
+	from, err := b.tableAlias(b.table, b.alias)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query += " from " + from
+
+	for _, j := range b.joins {
+		table, err := b.tableAlias(j.table, j.alias)
+		if err != nil {
+			return "", nil, err
+		}
+
+		on, err := bind(j.on)
+		if err != nil {
+			return "", nil, err
+		}
+
+		query += fmt.Sprintf(" %s %s on %s", j.kind.String(), table, on)
+	}
+
+	where := b.where
+	if len(b.keyset) != 0 {
+		keyset, err := b.keysetClause()
+		if err != nil {
+			return "", nil, err
+		}
+
+		where = append(where[:len(where):len(where)], keyset)
+	}
+
+	if len(where) != 0 {
+		conditions, err := joinClauses(where, bind)
+		if err != nil {
+			return "", nil, err
+		}
+
+		query += " where " + conditions
+	}
+
+	if len(b.groupBy) != 0 {
+		groupBy, err := b.dialect.QuoteIdentifiers(b.groupBy)
+		if err != nil {
+			return "", nil, err
+		}
+
+		query += " group by " + strings.Join(groupBy, ", ")
+	}
+
+	if len(b.having) != 0 {
+		conditions, err := joinClauses(b.having, bind)
+		if err != nil {
+			return "", nil, err
+		}
+
+		query += " having " + conditions
+	}
+
+	if len(b.orderBy) != 0 {
+		orders := make([]string, len(b.orderBy))
+		for i, o := range b.orderBy {
+			if !o.order.IsValid() {
+				return "", nil, fmt.Errorf("bad sort order %d for column %s", o.order, o.column)
+			}
+
+			column, err := b.dialect.QuoteIdentifier(o.column)
+			if err != nil {
+				return "", nil, err
+			}
+
+			orders[i] = column + " " + sqlOrder(o.order)
+		}
+
+		query += " order by " + strings.Join(orders, ", ")
+	}
+
+	pagination, err := b.pagination()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return query + pagination, args, nil
+}
+
+// keysetClause expands keyset values into
+// (c1 > v1) or (c1 = v1 and c2 > v2) or ...
+//
+// The expanded form is used instead of row value comparison
+// because it works for all dialects and for mixed sort orders.
+func (b *SelectBuilder) keysetClause() (clause, error) {
+	if len(b.orderBy) == 0 {
+		return clause{}, ErrKeysetNoOrder
+	}
+
+	if len(b.keyset) != len(b.orderBy) {
+		return clause{}, fmt.Errorf("%w: %d keyset values for %d order by columns", ErrKeysetCount, len(b.keyset), len(b.orderBy))
+	}
+
+	columns := make([]string, len(b.orderBy))
+	for i := range b.orderBy {
+		column, err := b.dialect.QuoteIdentifier(b.orderBy[i].column)
+		if err != nil {
+			return clause{}, err
+		}
+
+		columns[i] = column
+	}
+
+	var args []interface{}
+	ors := make([]string, len(b.orderBy))
+
+	for i := range b.orderBy {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, columns[j]+" = ?")
+			args = append(args, b.keyset[j])
+		}
+
+		op := ">"
+		if b.orderBy[i].order == data.Descending {
+			op = "<"
+		}
+
+		ands = append(ands, columns[i]+" "+op+" ?")
+		args = append(args, b.keyset[i])
+
+		ors[i] = "(" + strings.Join(ands, " and ") + ")"
+	}
+
+	return clause{
+		sql:  strings.Join(ors, " or "),
+		args: args,
+	}, nil
+}
+
+func (b *SelectBuilder) pagination() (string, error) {
+	if !b.hasLimit && b.offset == 0 {
+		return "", nil
+	}
+
+	limit := strconv.FormatUint(b.limit, 10)
+	offset := strconv.FormatUint(b.offset, 10)
+
+	switch b.dialect {
+	case Postgres:
+		var s string
+		if b.hasLimit {
+			s += " limit " + limit
+		}
+		if b.offset != 0 {
+			s += " offset " + offset
+		}
+
+		return s, nil
+
+	case MySQL, SQLite:
+		if !b.hasLimit {
+			// Both require LIMIT for OFFSET
+			limit = "18446744073709551615"
+			if b.dialect == SQLite {
+				limit = "-1"
+			}
+		}
+
+		s := " limit " + limit
+		if b.offset != 0 {
+			s += " offset " + offset
+		}
+
+		return s, nil
+
+	case SQLServer:
+		// SQL Server only supports OFFSET-FETCH with ORDER BY
+		if len(b.orderBy) == 0 {
+			return "", ErrOffsetNoOrder
+		}
+	}
+
+	// Oracle 12c+ and SQL Server
+	s := " offset " + offset + " rows"
+	if b.hasLimit {
+		s += " fetch next " + limit + " rows only"
+	}
+
+	return s, nil
+}
+
+func joinClauses(clauses []clause, bind func(clause) (string, error)) (string, error) {
+	conditions := make([]string, len(clauses))
+	for i, c := range clauses {
+		condition, err := bind(c)
+		if err != nil {
+			return "", err
+		}
+
+		if len(clauses) > 1 {
+			condition = "(" + condition + ")"
+		}
+
+		conditions[i] = condition
+	}
+
+	return strings.Join(conditions, " and "), nil
+}
+
+// tableAlias quotes and formats table alias without "as" keyword,
+// which is not supported by Oracle.
+func (b *SelectBuilder) tableAlias(table, alias string) (string, error) {
+	quoted, err := b.dialect.QuoteIdentifier(table)
+	if err != nil {
+		return "", err
+	}
+
+	if alias == "" {
+		return quoted, nil
+	}
+
+	if strings.Contains(alias, ".") {
+		return "", fmt.Errorf("%w: qualified alias %q", ErrInvalidIdentifier, alias)
+	}
+
+	quotedAlias, err := b.dialect.QuoteIdentifier(alias)
+	if err != nil {
+		return "", err
+	}
+
+	return quoted + " " + quotedAlias, nil
+}
+
+func sqlOrder(order data.SortOrder) string {
+	if order == data.Descending {
+		return "desc"
+	}
+
+	return "asc"
+}
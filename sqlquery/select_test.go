@@ -0,0 +1,140 @@
+package sqlquery
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soyart/gsl/data"
+)
+
+func TestSelect(t *testing.T) {
+	type test struct {
+		builder        *SelectBuilder
+		expectedQuery  string
+		expectedValues []interface{}
+	}
+
+	tests := []test{
+		{
+			builder:       Select(Postgres).From("users"),
+			expectedQuery: `select * from "users"`,
+		},
+		{
+			builder: Select(Postgres, "u.id", "u.name").
+				ColumnsRaw("count(p.id)").
+				FromAs("users", "u").
+				LeftJoin("posts", "p", "p.user_id = u.id and p.status = ?", "published").
+				Where("u.age > ?", 18).
+				Where("u.name like ? or u.name = 'a?b'", "soy%").
+				GroupBy("u.id", "u.name").
+				Having("count(p.id) > ?", 2).
+				OrderBy("u.name", data.Ascending).
+				Limit(10).
+				Offset(20),
+			expectedQuery: `select "u"."id", "u"."name", count(p.id) from "users" "u"` +
+				` left join "posts" "p" on p.user_id = u.id and p.status = $1` +
+				` where (u.age > $2) and (u.name like $3 or u.name = 'a?b')` +
+				` group by "u"."id", "u"."name" having count(p.id) > $4` +
+				` order by "u"."name" asc limit 10 offset 20`,
+			expectedValues: []interface{}{"published", 18, "soy%", 2},
+		},
+		{
+			builder: Select(MySQL, "id").
+				From("users").
+				Join("roles", "", "roles.id = users.role_id").
+				Where("data ?? 'key'").
+				Offset(5),
+			expectedQuery: "select `id` from `users` inner join `roles` on roles.id = users.role_id" +
+				" where data ?? 'key' limit 18446744073709551615 offset 5",
+		},
+		{
+			builder:       Select(SQLite, "u.*").FromAs("main.users", "u").Offset(5),
+			expectedQuery: `select "u".* from "main"."users" "u" limit -1 offset 5`,
+		},
+		{
+			builder:       Select(SQLite).From("users").Offset(5),
+			expectedQuery: `select * from "users" limit -1 offset 5`,
+		},
+		{
+			builder: Select(Oracle, "id").
+				FromAs("users", "u").
+				Where("id = ?", 1).
+				Limit(10),
+			expectedQuery:  `select "id" from "users" "u" where id = :1 offset 0 rows fetch next 10 rows only`,
+			expectedValues: []interface{}{1},
+		},
+		{
+			builder: Select(SQLServer, "id").
+				From("users").
+				Where("id > ?", 1).
+				OrderBy("id", data.Descending).
+				Limit(10).
+				Offset(10),
+			expectedQuery:  "select [id] from [users] where id > @p1 order by [id] desc offset 10 rows fetch next 10 rows only",
+			expectedValues: []interface{}{1},
+		},
+		{
+			// Keyset pagination with mixed orders
+			builder: Select(Postgres, "id").
+				From("posts").
+				Where("user_id = ?", 7).
+				OrderBy("created_at", data.Descending).
+				OrderBy("id", data.Ascending).
+				After("2024-01-01", 100).
+				Limit(20),
+			expectedQuery: `select "id" from "posts" where (user_id = $1)` +
+				` and (("created_at" < $2) or ("created_at" = $3 and "id" > $4))` +
+				` order by "created_at" desc, "id" asc limit 20`,
+			expectedValues: []interface{}{7, "2024-01-01", "2024-01-01", 100},
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		query, values, err := test.builder.Build()
+		if err != nil {
+			t.Fatalf("[%d] unexpected error: %s", i, err.Error())
+		}
+
+		if query != test.expectedQuery {
+			t.Logf("Expecting:\n\"%s\"", test.expectedQuery)
+			t.Logf("Actual:\n\"%s\"", query)
+
+			t.Fatalf("[%d] unexpected query", i)
+		}
+
+		assertEqual(t, "values", test.expectedValues, values)
+	}
+}
+
+func TestSelectErrors(t *testing.T) {
+	type test struct {
+		builder  *SelectBuilder
+		expected error
+	}
+
+	tests := []test{
+		{builder: Select(Dialect(0)).From("users"), expected: ErrInvalidDialect},
+		{builder: Select(Postgres), expected: ErrNoTable},
+		{builder: Select(Postgres).From("users").Where("id = ? or id = ?", 1), expected: ErrBadBindCount},
+		{builder: Select(Postgres).From("users").After(1), expected: ErrKeysetNoOrder},
+		{builder: Select(Postgres).From("users").OrderBy("id", data.Ascending).After(1, 2), expected: ErrKeysetCount},
+		{builder: Select(SQLServer).From("users").Limit(1), expected: ErrOffsetNoOrder},
+		{builder: Select(Postgres).From("users; drop table users"), expected: ErrInvalidIdentifier},
+		{builder: Select(Postgres, "id, password").From("users"), expected: ErrInvalidIdentifier},
+		{builder: Select(Postgres).FromAs("users", "u.x"), expected: ErrInvalidIdentifier},
+		{builder: Select(Postgres).From("users").Join("roles", "r\"", "true"), expected: ErrInvalidIdentifier},
+		{builder: Select(Postgres).From("users").GroupBy("1=1"), expected: ErrInvalidIdentifier},
+		{builder: Select(Postgres).From("users").OrderBy("id--", data.Ascending), expected: ErrInvalidIdentifier},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		_, _, err := test.builder.Build()
+		if !errors.Is(err, test.expected) {
+			t.Fatalf("[%d] expecting error %v, got %v", i, test.expected, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package sqlquery
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Placeholder uint8
@@ -10,8 +11,37 @@ const (
 	QuestionMark Placeholder = iota + 1
 	Dollar
 	Colon
+	AtP // SQL Server style @p1, @p2, ...
 )
 
+func (p Placeholder) IsValid() bool {
+	switch p {
+	case QuestionMark, Dollar, Colon, AtP:
+		return true
+	}
+
+	return false
+}
+
+// Bind returns the placeholder string for the |n|-th bind variable (1-based).
+func (p Placeholder) Bind(n uint) string {
+	switch p {
+	case QuestionMark:
+		return "?"
+
+	case Dollar:
+		return fmt.Sprintf("$%d", n)
+
+	case Colon:
+		return fmt.Sprintf(":%d", n)
+
+	case AtP:
+		return fmt.Sprintf("@p%d", n)
+	}
+
+	panic(fmt.Sprintf("invalid placeholder %d", p))
+}
+
 func ClauseColumns(columns []string) string {
 	lenColumns := len(columns)
 	if lenColumns == 0 {
@@ -45,6 +75,9 @@ func ClauseValues(placeholder Placeholder, start, lenColumns uint) string {
 
 	case QuestionMark:
 		return ClauseValuesQuestionMark(lenColumns)
+
+	case AtP:
+		return clauseValuesBind(placeholder, start, lenColumns)
 	}
 
 	panic(fmt.Sprintf("invalid placeholder %d", placeholder))
@@ -79,3 +112,60 @@ func ClauseValuesNumbered(start, lenColumns uint, placeholder rune) string {
 
 	return clause
 }
+
+func clauseValuesBind(placeholder Placeholder, start, lenColumns uint) string {
+	clause := "("
+	for i := uint(0); i < lenColumns; i++ {
+		clause += placeholder.Bind(start + i)
+
+		if i != lenColumns-1 {
+			clause += ","
+		}
+	}
+
+	clause += ")"
+
+	return clause
+}
+
+// Rebind rewrites question mark bind variables in |query| to |placeholder|,
+// numbered from |start|. Question marks inside quoted strings, quoted identifiers
+// and comments are left untouched. "??" escapes a literal "?" (e.g. for Postgres
+// JSON operators), and is rewritten to "?" unless |placeholder| is QuestionMark.
+//
+// It returns the rewritten query and the number of bind variables found.
+func Rebind(query string, placeholder Placeholder, start uint) (string, uint) {
+	var out strings.Builder
+	var count uint
+
+	for i := 0; i < len(query); {
+		if end := skipQuoted(query, i); end != i {
+			out.WriteString(query[i:end])
+			i = end
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(query[i:], "??"):
+			if placeholder == QuestionMark {
+				out.WriteString("??")
+			} else {
+				out.WriteByte('?')
+			}
+
+			i += 2
+
+		case query[i] == '?':
+			out.WriteString(placeholder.Bind(start + count))
+			count++
+			i++
+
+		default:
+			out.WriteByte(query[i])
+			i++
+		}
+	}
+
+	return out.String(), count
+}
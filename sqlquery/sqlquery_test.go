@@ -161,3 +161,35 @@ func TestFmtClauseValuesDollar(t *testing.T) {
 		}
 	}
 }
+
+func TestRebind(t *testing.T) {
+	type test struct {
+		query         string
+		placeholder   Placeholder
+		start         uint
+		expected      string
+		expectedCount uint
+	}
+
+	tests := []test{
+		{query: "a = ? and b = ?", placeholder: Dollar, start: 1, expected: "a = $1 and b = $2", expectedCount: 2},
+		{query: "a = ? and b = ?", placeholder: Colon, start: 3, expected: "a = :3 and b = :4", expectedCount: 2},
+		{query: "a = ? and b = ?", placeholder: AtP, start: 1, expected: "a = @p1 and b = @p2", expectedCount: 2},
+		{query: "a = ? and b = '?'", placeholder: QuestionMark, start: 1, expected: "a = ? and b = '?'", expectedCount: 1},
+		{query: "j ?? 'k' and \"?\" = ?", placeholder: Dollar, start: 1, expected: "j ? 'k' and \"?\" = $1", expectedCount: 1},
+		{query: "j ?? 'k' and a = ?", placeholder: QuestionMark, start: 1, expected: "j ?? 'k' and a = ?", expectedCount: 1},
+		{query: "`?` = ? -- ?\n/* ? */ and b = ?", placeholder: Colon, start: 1, expected: "`?` = :1 -- ?\n/* ? */ and b = :2", expectedCount: 2},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		result, count := Rebind(test.query, test.placeholder, test.start)
+		if result != test.expected || count != test.expectedCount {
+			t.Logf("Expecting: \"%s\" (%d)", test.expected, test.expectedCount)
+			t.Logf("Got: \"%s\" (%d)", result, count)
+
+			t.Fatalf("unexpected rebind result")
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package gsl
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/constraints"
+)
+
+var ErrOverflow = errors.New("integer overflow")
+
+// defaultCompression is the t-digest compression of Stats,
+// which keeps at most a few hundred centroids
+const defaultCompression = 100
+
+// Stats is a streaming accumulator of count, mean, variance, min, max
+// and approximate percentiles, using O(1) memory for exact statistics
+// and a t-digest for percentiles.
+//
+// Mean and variance are computed with Welford's algorithm, which is numerically stable.
+// Percentiles are exact for small inputs (up to a few hundred values),
+// and approximate for larger inputs, with better accuracy at the tails.
+//
+// Stats is not safe for concurrent use: use one Stats per goroutine and Merge them.
+// The zero value is an empty Stats ready to use.
+type Stats[N GoNumber] struct {
+	count uint64
+	mean  float64
+	m2    float64
+	min   N
+	max   N
+
+	digest tDigest
+}
+
+func NewStats[N GoNumber](values ...N) *Stats[N] {
+	s := new(Stats[N])
+	s.Add(values...)
+
+	return s
+}
+
+func (s *Stats[N]) Add(values ...N) {
+	for _, value := range values {
+		s.add(value)
+	}
+}
+
+func (s *Stats[N]) add(value N) {
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+
+	x := float64(value)
+
+	s.count++
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+
+	s.digest.add(x, 1)
+}
+
+// Merge adds all values added to |other| into |s|, using Chan et al.'s
+// parallel algorithm for mean and variance. |other| is not modified.
+func (s *Stats[N]) Merge(other *Stats[N]) {
+	if other.count == 0 {
+		return
+	}
+
+	if s.count == 0 {
+		s.count, s.mean, s.m2, s.min, s.max = other.count, other.mean, other.m2, other.min, other.max
+		s.digest.merge(&other.digest)
+
+		return
+	}
+
+	n, m := float64(s.count), float64(other.count)
+	delta := other.mean - s.mean
+
+	s.mean += delta * m / (n + m)
+	s.m2 += other.m2 + delta*delta*n*m/(n+m)
+	s.count += other.count
+	s.min = min(s.min, other.min)
+	s.max = max(s.max, other.max)
+
+	s.digest.merge(&other.digest)
+}
+
+func (s *Stats[N]) Count() uint64 {
+	return s.count
+}
+
+// Min returns the minimum value, or zero value if there is none.
+func (s *Stats[N]) Min() N {
+	return s.min
+}
+
+// Max returns the maximum value, or zero value if there is none.
+func (s *Stats[N]) Max() N {
+	return s.max
+}
+
+// Mean returns the arithmetic mean, or NaN if there is no value.
+func (s *Stats[N]) Mean() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+
+	return s.mean
+}
+
+// Variance returns the population variance, or NaN if there is no value.
+func (s *Stats[N]) Variance() float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+
+	return s.m2 / float64(s.count)
+}
+
+// SampleVariance returns the sample variance (with Bessel's correction),
+// or NaN if there are fewer than 2 values.
+func (s *Stats[N]) SampleVariance() float64 {
+	if s.count < 2 {
+		return math.NaN()
+	}
+
+	return s.m2 / float64(s.count-1)
+}
+
+// StdDev returns the population standard deviation, or NaN if there is no value.
+func (s *Stats[N]) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Median returns the approximate median, or NaN if there is no value.
+func (s *Stats[N]) Median() float64 {
+	return s.Percentile(50)
+}
+
+// Percentile returns the approximate |p|-th percentile, where 0 <= |p| <= 100,
+// or NaN if there is no value or |p| is out of range.
+func (s *Stats[N]) Percentile(p float64) float64 {
+	if s.count == 0 || p < 0 || p > 100 || math.IsNaN(p) {
+		return math.NaN()
+	}
+
+	return s.digest.quantile(p/100, float64(s.min), float64(s.max))
+}
+
+// Median returns the median of |items|, or NaN if |items| is empty.
+// |items| is not modified.
+func Median[N GoNumber](items ...N) float64 {
+	return Percentile(50, items...)
+}
+
+// Percentile returns the |p|-th percentile of |items|, where 0 <= |p| <= 100,
+// interpolated linearly between the closest ranks (like NumPy's default).
+// It returns NaN if |items| is empty or |p| is out of range. |items| is not modified.
+func Percentile[N GoNumber](p float64, items ...N) float64 {
+	if len(items) == 0 || p < 0 || p > 100 || math.IsNaN(p) {
+		return math.NaN()
+	}
+
+	sorted := CopySlice(items)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+
+	return float64(sorted[lo]) + (rank-float64(lo))*(float64(sorted[hi])-float64(sorted[lo]))
+}
+
+// Mode returns the most frequent values of |items| in ascending order,
+// or nil if |items| is empty.
+func Mode[N GoNumber](items ...N) []N {
+	counts := CountBy(items, func(item N) N { return item })
+
+	var modes []N
+	var most int
+
+	for item, count := range counts {
+		switch {
+		case count > most:
+			modes = append(modes[:0], item)
+			most = count
+		case count == most:
+			modes = append(modes, item)
+		}
+	}
+
+	sort.Slice(modes, func(i, j int) bool {
+		return modes[i] < modes[j]
+	})
+
+	return modes
+}
+
+// Variance returns the population variance of |items|, or NaN if |items| is empty.
+func Variance[N GoNumber](items ...N) float64 {
+	return NewStats(items...).Variance()
+}
+
+// StdDev returns the population standard deviation of |items|, or NaN if |items| is empty.
+func StdDev[N GoNumber](items ...N) float64 {
+	return NewStats(items...).StdDev()
+}
+
+// SumChecked is like Sum for integers, but returns ErrOverflow
+// if the sum overflows or underflows N.
+func SumChecked[N constraints.Integer](items ...N) (N, error) {
+	var sum N
+	for _, item := range items {
+		next := sum + item
+		if (item > 0 && next < sum) || (item < 0 && next > sum) {
+			return sum, errors.Wrapf(ErrOverflow, "sum %v + %v", sum, item)
+		}
+
+		sum = next
+	}
+
+	return sum, nil
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// tDigest is a merging t-digest (Dunning & Ertl), which summarizes values
+// into centroids that are small at the tails and large in the middle.
+// Values are buffered and merged into centroids when the buffer is full.
+type tDigest struct {
+	centroids []centroid
+	buffer    []centroid
+	weight    float64
+}
+
+func (d *tDigest) add(x, weight float64) {
+	d.buffer = append(d.buffer, centroid{mean: x, weight: weight})
+	d.weight += weight
+
+	if len(d.buffer) >= 5*defaultCompression {
+		d.compress()
+	}
+}
+
+func (d *tDigest) merge(other *tDigest) {
+	for _, c := range other.centroids {
+		d.add(c.mean, c.weight)
+	}
+
+	for _, c := range other.buffer {
+		d.add(c.mean, c.weight)
+	}
+}
+
+// compress merges buffered values into centroids, such that each centroid's
+// weight is at most 4 * W * q * (1 - q) / compression, where q is its quantile
+func (d *tDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	curr := all[0]
+	var before float64
+
+	for _, c := range all[1:] {
+		q := (before + (curr.weight+c.weight)/2) / d.weight
+		limit := 4 * d.weight * q * (1 - q) / defaultCompression
+
+		if curr.weight+c.weight <= limit {
+			curr.weight += c.weight
+			curr.mean += (c.mean - curr.mean) * c.weight / curr.weight
+
+			continue
+		}
+
+		merged = append(merged, curr)
+		before += curr.weight
+		curr = c
+	}
+
+	d.centroids = append(merged, curr)
+	d.buffer = d.buffer[:0]
+}
+
+// quantile returns |q|-th quantile, interpolating between centroid centers,
+// and between the extremes |lo| and |hi| at the tails
+func (d *tDigest) quantile(q, lo, hi float64) float64 {
+	d.compress()
+
+	if len(d.centroids) == 0 {
+		return math.NaN()
+	}
+
+	// Singleton centroids are exact values, so q = 0 and q = 1 are min and max
+	target := q * (d.weight - 1)
+
+	var before float64
+	prevCenter, prevMean := 0.0, lo
+
+	for _, c := range d.centroids {
+		// Rank of the centroid's center, with ranks from 0 to W - 1
+		center := before + (c.weight-1)/2
+		if target <= center {
+			if center == prevCenter {
+				return c.mean
+			}
+
+			return prevMean + (target-prevCenter)/(center-prevCenter)*(c.mean-prevMean)
+		}
+
+		before += c.weight
+		prevCenter, prevMean = center, c.mean
+	}
+
+	last := d.weight - 1
+	if last == prevCenter {
+		return hi
+	}
+
+	return prevMean + (target-prevCenter)/(last-prevCenter)*(hi-prevMean)
+}
@@ -0,0 +1,157 @@
+package gsl
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func almostEqual(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance
+}
+
+func TestStats(t *testing.T) {
+	var s Stats[int]
+	if !math.IsNaN(s.Mean()) || !math.IsNaN(s.Median()) || s.Count() != 0 {
+		t.Fatalf("unexpected stats from empty Stats")
+	}
+
+	s.Add(2, 4, 4, 4, 5, 5, 7, 9)
+
+	type test struct {
+		name     string
+		actual   float64
+		expected float64
+	}
+
+	tests := []test{
+		{name: "mean", actual: s.Mean(), expected: 5},
+		{name: "variance", actual: s.Variance(), expected: 4},
+		{name: "sample variance", actual: s.SampleVariance(), expected: 32.0 / 7},
+		{name: "stddev", actual: s.StdDev(), expected: 2},
+		{name: "median", actual: s.Median(), expected: 4.5},
+		{name: "p0", actual: s.Percentile(0), expected: 2},
+		{name: "p100", actual: s.Percentile(100), expected: 9},
+		{name: "p25", actual: s.Percentile(25), expected: 4},
+		{name: "min", actual: float64(s.Min()), expected: 2},
+		{name: "max", actual: float64(s.Max()), expected: 9},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if !almostEqual(test.actual, test.expected, 1e-9) {
+			t.Logf("Expecting %v", test.expected)
+			t.Fatalf("[%s] unexpected value %v", test.name, test.actual)
+		}
+	}
+
+	if !math.IsNaN(s.Percentile(101)) {
+		t.Fatalf("expecting NaN for out of range percentile")
+	}
+}
+
+func TestStatsMerge(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = rng.NormFloat64()*10 + 50
+	}
+
+	// Accumulate in parallel, then merge
+	const workers = 4
+	parts := make([]Stats[float64], workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			for i := w; i < len(values); i += workers {
+				parts[w].Add(values[i])
+			}
+		}(w)
+	}
+
+	wg.Wait()
+
+	var merged Stats[float64]
+	for w := range parts {
+		merged.Merge(&parts[w])
+	}
+
+	whole := NewStats(values...)
+
+	if merged.Count() != whole.Count() || merged.Min() != whole.Min() || merged.Max() != whole.Max() {
+		t.Fatalf("unexpected merged count, min or max")
+	}
+
+	if !almostEqual(merged.Mean(), whole.Mean(), 1e-9) || !almostEqual(merged.Variance(), whole.Variance(), 1e-6) {
+		t.Fatalf("unexpected merged mean or variance: %v %v, expecting %v %v",
+			merged.Mean(), merged.Variance(), whole.Mean(), whole.Variance())
+	}
+
+	for _, p := range []float64{1, 10, 50, 90, 99} {
+		exact := Percentile(p, values...)
+
+		for _, s := range []*Stats[float64]{whole, &merged} {
+			// Allow 0.5% of the quantile error in rank
+			lo, hi := Percentile(p-0.5, values...), Percentile(p+0.5, values...)
+			if actual := s.Percentile(p); actual < lo || actual > hi {
+				t.Fatalf("p%v too far from exact value %v: got %v, expecting [%v, %v]", p, exact, actual, lo, hi)
+			}
+		}
+	}
+}
+
+func TestSliceStats(t *testing.T) {
+	items := []int{5, 1, 3, 3, 9, 1}
+
+	if m := Median(items...); m != 3 {
+		t.Fatalf("unexpected median %v", m)
+	}
+
+	if items[0] != 5 {
+		t.Fatalf("Median modified its input")
+	}
+
+	if p := Percentile(90, items...); !almostEqual(p, 7, 1e-9) {
+		t.Fatalf("unexpected p90 %v", p)
+	}
+
+	if modes := Mode(items...); !reflect.DeepEqual(modes, []int{1, 3}) {
+		t.Fatalf("unexpected modes %v", modes)
+	}
+
+	if Mode[int]() != nil || !math.IsNaN(Median[int]()) {
+		t.Fatalf("unexpected results from empty input")
+	}
+
+	if sd := StdDev(2.0, 4, 4, 4, 5, 5, 7, 9); !almostEqual(sd, 2, 1e-9) {
+		t.Fatalf("unexpected stddev %v", sd)
+	}
+}
+
+func TestSumChecked(t *testing.T) {
+	if sum, err := SumChecked[int8](100, 27, -50); err != nil || sum != 77 {
+		t.Fatalf("unexpected sum %d, err %v", sum, err)
+	}
+
+	if _, err := SumChecked[int8](100, 28); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expecting ErrOverflow, got %v", err)
+	}
+
+	if _, err := SumChecked[int8](-100, -29); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expecting ErrOverflow for underflow, got %v", err)
+	}
+
+	if _, err := SumChecked[uint64](math.MaxUint64, 1); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expecting ErrOverflow for uint64, got %v", err)
+	}
+}
@@ -2,6 +2,11 @@ package gsl
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
 
 	"github.com/soyart/gsl/data/list"
 )
@@ -94,6 +99,7 @@ func OpenChar(closeChar rune) (rune, error) {
 // 5. [             -> error
 //
 // Notes: Only openning/closing characters are concerned.
+// See DelimiterSet for quotes, comments, custom delimiters and error positions.
 func IsWellClosed(s string) error {
 	stack := list.NewStackSafe[rune]()
 
@@ -133,3 +139,367 @@ func IsWellClosed(s string) error {
 
 	return nil
 }
+
+var (
+	ErrUnexpectedClose     = errors.New("unexpected close delimiter")
+	ErrMismatchedClose     = errors.New("mismatched close delimiter")
+	ErrUnclosed            = errors.New("unclosed delimiter")
+	ErrUnterminatedQuote   = errors.New("unterminated quote")
+	ErrUnterminatedComment = errors.New("unterminated comment")
+)
+
+// DelimiterPair is a pair of open and close delimiters, e.g. "(" and ")", or "begin" and "end".
+// If Open and Close are equal, e.g. "|", the delimiter alternates between opening and closing.
+type DelimiterPair struct {
+	Open  string
+	Close string
+}
+
+// QuoteRule describes quoted strings, inside which delimiters are ignored.
+type QuoteRule struct {
+	Quote string
+
+	// Escape, if not empty, escapes the next character inside quotes, e.g. "\\"
+	Escape string
+
+	// Doubled allows escaping Quote by doubling it, e.g. 'it''s' in SQL
+	Doubled bool
+}
+
+// CommentRule describes comments, inside which delimiters are ignored.
+// If End is empty, the comment ends at the end of the line.
+type CommentRule struct {
+	Start string
+	End   string
+}
+
+// DelimiterSet is a configurable delimiter validator and parser.
+// When tokens overlap, e.g. "/*" and "/", the longest token wins.
+type DelimiterSet struct {
+	Pairs    []DelimiterPair
+	Quotes   []QuoteRule
+	Comments []CommentRule
+}
+
+// DefaultDelimiterSet returns the DelimiterSet with the brackets of IsWellClosed,
+// i.e. (), {}, [] and <>, with no quotes or comments.
+func DefaultDelimiterSet() DelimiterSet {
+	return DelimiterSet{
+		Pairs: []DelimiterPair{
+			{Open: string(paren), Close: string(parenClose)},
+			{Open: string(curlyBrace), Close: string(curlyBraceClose)},
+			{Open: string(sqBracket), Close: string(sqBracketClose)},
+			{Open: string(chevron), Close: string(chevronClose)},
+		},
+	}
+}
+
+// Position is a position in the input. Line and Column are 1-based,
+// and Column counts runes, while Offset is the 0-based byte offset.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// DelimiterError is an error at a position, and wraps one of
+// ErrUnexpectedClose, ErrMismatchedClose, ErrUnclosed, ErrUnterminatedQuote and ErrUnterminatedComment.
+type DelimiterError struct {
+	Err      error
+	Pos      Position
+	Found    string
+	Expected string
+}
+
+func (e *DelimiterError) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("%s: %s '%s' - expecting '%s'", e.Pos, e.Err.Error(), e.Found, e.Expected)
+	}
+
+	return fmt.Sprintf("%s: %s '%s'", e.Pos, e.Err.Error(), e.Found)
+}
+
+func (e *DelimiterError) Unwrap() error {
+	return e.Err
+}
+
+// Span is a matched pair of delimiters, with spans nested inside it as Children.
+type Span struct {
+	Pair     DelimiterPair
+	Open     Position
+	Close    Position
+	Children []*Span
+}
+
+// Inner returns the text between the delimiters of |span| in |s|.
+func (span *Span) Inner(s string) string {
+	return s[span.Open.Offset+len(span.Pair.Open) : span.Close.Offset]
+}
+
+// Validate returns the first error in |s|, or nil if all delimiters are well closed.
+func (d DelimiterSet) Validate(s string) error {
+	_, errs := d.Parse(s)
+	if len(errs) != 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// Parse returns the tree of matched spans in |s|, and all errors in order of position.
+//
+// Parsing continues after errors: a mismatched close delimiter closes its matching
+// open delimiter if there is one (reporting open delimiters in between as unclosed),
+// and is skipped otherwise. Unterminated quotes and comments end the input.
+func (d DelimiterSet) Parse(s string) ([]*Span, []*DelimiterError) {
+	p := delimiterParser{set: d, s: s, line: 1, column: 1}
+	p.parse()
+
+	sort.SliceStable(p.errs, func(i, j int) bool {
+		return p.errs[i].Pos.Offset < p.errs[j].Pos.Offset
+	})
+
+	return p.roots, p.errs
+}
+
+type delimiterParser struct {
+	set DelimiterSet
+	s   string
+
+	offset int
+	line   int
+	column int
+
+	roots []*Span
+	stack []*Span
+	errs  []*DelimiterError
+}
+
+func (p *delimiterParser) pos() Position {
+	return Position{
+		Offset: p.offset,
+		Line:   p.line,
+		Column: p.column,
+	}
+}
+
+// advance moves to offset |to|, keeping track of lines and columns,
+// so that pos does not recount runes from the start of the line
+func (p *delimiterParser) advance(to int) {
+	for p.offset < to {
+		r, size := utf8.DecodeRuneInString(p.s[p.offset:to])
+		p.offset += size
+
+		if r == '\n' {
+			p.line++
+			p.column = 1
+			continue
+		}
+
+		p.column++
+	}
+}
+
+func (p *delimiterParser) fail(err error, pos Position, found, expected string) {
+	p.errs = append(p.errs, &DelimiterError{Err: err, Pos: pos, Found: found, Expected: expected})
+}
+
+func (p *delimiterParser) parse() {
+loop:
+	for p.offset < len(p.s) {
+		rest := p.s[p.offset:]
+
+		comment, quote, pair := p.longestToken(rest)
+
+		switch {
+		case comment != nil:
+			if !p.skipComment(*comment) {
+				break loop
+			}
+
+		case quote != nil:
+			if !p.skipQuote(*quote) {
+				break loop
+			}
+
+		case pair != nil:
+			p.delimiter(*pair, rest)
+
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			p.advance(p.offset + size)
+		}
+	}
+
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		p.fail(ErrUnclosed, p.stack[i].Open, p.stack[i].Pair.Open, "")
+	}
+
+	// Matched spans inside unclosed spans are kept
+	for i := range p.stack {
+		p.roots = append(p.roots, p.stack[i].Children...)
+	}
+}
+
+// longestToken returns the longest comment, quote or delimiter starting |rest|
+func (p *delimiterParser) longestToken(rest string) (*CommentRule, *QuoteRule, *DelimiterPair) {
+	var comment *CommentRule
+	var quote *QuoteRule
+	var pair *DelimiterPair
+	var longest int
+
+	for i := range p.set.Comments {
+		if start := p.set.Comments[i].Start; start != "" && len(start) > longest && strings.HasPrefix(rest, start) {
+			comment, quote, pair, longest = &p.set.Comments[i], nil, nil, len(start)
+		}
+	}
+
+	for i := range p.set.Quotes {
+		if q := p.set.Quotes[i].Quote; q != "" && len(q) > longest && strings.HasPrefix(rest, q) {
+			comment, quote, pair, longest = nil, &p.set.Quotes[i], nil, len(q)
+		}
+	}
+
+	for i := range p.set.Pairs {
+		for _, token := range []string{p.set.Pairs[i].Open, p.set.Pairs[i].Close} {
+			if token != "" && len(token) > longest && strings.HasPrefix(rest, token) {
+				comment, quote, pair, longest = nil, nil, &p.set.Pairs[i], len(token)
+			}
+		}
+	}
+
+	return comment, quote, pair
+}
+
+func (p *delimiterParser) skipComment(comment CommentRule) bool {
+	start := p.pos()
+	body := p.offset + len(comment.Start)
+
+	if comment.End == "" {
+		end := strings.IndexByte(p.s[body:], '\n')
+		if end == -1 {
+			p.advance(len(p.s))
+			return false
+		}
+
+		p.advance(body + end + 1)
+		return true
+	}
+
+	end := strings.Index(p.s[body:], comment.End)
+	if end == -1 {
+		p.fail(ErrUnterminatedComment, start, comment.Start, comment.End)
+		p.advance(len(p.s))
+
+		return false
+	}
+
+	p.advance(body + end + len(comment.End))
+	return true
+}
+
+func (p *delimiterParser) skipQuote(quote QuoteRule) bool {
+	start := p.pos()
+
+	for i := p.offset + len(quote.Quote); i < len(p.s); {
+		rest := p.s[i:]
+
+		switch {
+		case quote.Escape != "" && strings.HasPrefix(rest, quote.Escape):
+			i += len(quote.Escape)
+			if i < len(p.s) {
+				_, size := utf8.DecodeRuneInString(p.s[i:])
+				i += size
+			}
+
+		case strings.HasPrefix(rest, quote.Quote):
+			i += len(quote.Quote)
+			if quote.Doubled && strings.HasPrefix(p.s[i:], quote.Quote) {
+				i += len(quote.Quote)
+				continue
+			}
+
+			p.advance(i)
+			return true
+
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			i += size
+		}
+	}
+
+	p.fail(ErrUnterminatedQuote, start, quote.Quote, quote.Quote)
+	p.advance(len(p.s))
+
+	return false
+}
+
+func (p *delimiterParser) delimiter(pair DelimiterPair, rest string) {
+	pos := p.pos()
+
+	isOpen := strings.HasPrefix(rest, pair.Open)
+	isClose := strings.HasPrefix(rest, pair.Close)
+
+	// Symmetric delimiters close if the innermost span is of the same pair
+	if isOpen && isClose {
+		isOpen = len(p.stack) == 0 || p.stack[len(p.stack)-1].Pair != pair
+	}
+
+	if isOpen {
+		p.stack = append(p.stack, &Span{Pair: pair, Open: pos})
+		p.advance(p.offset + len(pair.Open))
+
+		return
+	}
+
+	p.advance(p.offset + len(pair.Close))
+
+	// Find the innermost span closed by this delimiter
+	match := -1
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].Pair.Close == pair.Close {
+			match = i
+			break
+		}
+	}
+
+	if match == -1 {
+		if len(p.stack) == 0 {
+			p.fail(ErrUnexpectedClose, pos, pair.Close, "")
+			return
+		}
+
+		p.fail(ErrMismatchedClose, pos, pair.Close, p.stack[len(p.stack)-1].Pair.Close)
+		return
+	}
+
+	if match != len(p.stack)-1 {
+		p.fail(ErrMismatchedClose, pos, pair.Close, p.stack[len(p.stack)-1].Pair.Close)
+
+		for i := len(p.stack) - 1; i > match; i-- {
+			p.fail(ErrUnclosed, p.stack[i].Open, p.stack[i].Pair.Open, "")
+		}
+	}
+
+	span := p.stack[match]
+
+	// Matched spans inside unclosed spans are kept
+	for i := match + 1; i < len(p.stack); i++ {
+		span.Children = append(span.Children, p.stack[i].Children...)
+	}
+
+	span.Close = pos
+	p.stack = p.stack[:match]
+
+	if len(p.stack) == 0 {
+		p.roots = append(p.roots, span)
+		return
+	}
+
+	parent := p.stack[len(p.stack)-1]
+	parent.Children = append(parent.Children, span)
+}
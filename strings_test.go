@@ -1,6 +1,12 @@
 package gsl
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
 
 func TestIsWellFormed(t *testing.T) {
 	shouldOk := []string{
@@ -37,3 +43,118 @@ func TestIsWellFormed(t *testing.T) {
 		}
 	}
 }
+
+func TestDelimiterSetValidate(t *testing.T) {
+	set := DelimiterSet{
+		Pairs: append(DefaultDelimiterSet().Pairs,
+			DelimiterPair{Open: "begin", Close: "end"},
+			DelimiterPair{Open: "|", Close: "|"},
+		),
+		Quotes: []QuoteRule{
+			{Quote: `"`, Escape: `\`},
+			{Quote: "'", Doubled: true},
+		},
+		Comments: []CommentRule{
+			{Start: "//"},
+			{Start: "/*", End: "*/"},
+		},
+	}
+
+	type test struct {
+		s   string
+		err error
+		pos Position
+	}
+
+	tests := []test{
+		{s: `f("(", ')')`},
+		{s: `f("\")", 'it'')s')`},
+		{s: "f() // )\n[]"},
+		{s: "f(/* ) */)"},
+		{s: "begin f(|x|) end"},
+		{s: "|a(|)", err: ErrUnclosed, pos: Position{Offset: 0, Line: 1, Column: 1}},
+		{s: "f(\n  x])", err: ErrMismatchedClose, pos: Position{Offset: 6, Line: 2, Column: 4}},
+		{s: "ก)", err: ErrUnexpectedClose, pos: Position{Offset: 3, Line: 1, Column: 2}},
+		{s: "f(\"x)", err: ErrUnclosed, pos: Position{Offset: 1, Line: 1, Column: 2}},
+		{s: "x\n/* (", err: ErrUnterminatedComment, pos: Position{Offset: 2, Line: 2, Column: 1}},
+		{s: "x 'it''", err: ErrUnterminatedQuote, pos: Position{Offset: 2, Line: 1, Column: 3}},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		err := set.Validate(test.s)
+
+		if test.err == nil {
+			if err != nil {
+				t.Fatalf("[%d] unexpected error for %q: %s", i, test.s, err.Error())
+			}
+
+			continue
+		}
+
+		var delimErr *DelimiterError
+		if !errors.Is(err, test.err) || !errors.As(err, &delimErr) {
+			t.Logf("Expecting %v", test.err)
+			t.Fatalf("[%d] unexpected error for %q: %v", i, test.s, err)
+		}
+
+		if delimErr.Pos != test.pos {
+			t.Fatalf("[%d] unexpected error position %+v, expecting %+v", i, delimErr.Pos, test.pos)
+		}
+	}
+
+	// Default set agrees with IsWellClosed
+	for _, s := range []string{"", "[()[{}]]<>", "foo(bar[baz])", "[", "[)", "[[(]]", "foo[(]"} {
+		if (IsWellClosed(s) == nil) != (DefaultDelimiterSet().Validate(s) == nil) {
+			t.Fatalf("DefaultDelimiterSet disagrees with IsWellClosed on %q", s)
+		}
+	}
+}
+
+func TestDelimiterSetParse(t *testing.T) {
+	s := "a(b[c]{d}) (e] x) }"
+	spans, errs := DefaultDelimiterSet().Parse(s)
+
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+
+	expected := []string{
+		"line 1, column 14: mismatched close delimiter ']' - expecting ')'",
+		"line 1, column 19: unexpected close delimiter '}'",
+	}
+
+	if !reflect.DeepEqual(expected, msgs) {
+		t.Fatalf("unexpected errors:\n%s", strings.Join(msgs, "\n"))
+	}
+
+	if len(spans) != 2 || spans[0].Inner(s) != "b[c]{d}" || spans[1].Inner(s) != "e] x" {
+		t.Fatalf("unexpected root spans %+v", spans)
+	}
+
+	children := spans[0].Children
+	if len(children) != 2 || children[0].Inner(s) != "c" || children[1].Pair.Open != "{" || children[1].Close.Column != 9 {
+		t.Fatalf("unexpected child spans %+v", children)
+	}
+
+	// Mismatched close recovers by closing the matching open delimiter
+	spans, errs = DefaultDelimiterSet().Parse("{ ( [] }")
+	if len(errs) != 2 || !errors.Is(errs[0], ErrUnclosed) || !errors.Is(errs[1], ErrMismatchedClose) {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	if len(spans) != 1 || len(spans[0].Children) != 1 || spans[0].Children[0].Pair.Open != "[" {
+		t.Fatalf("unexpected spans after recovery %+v", spans)
+	}
+}
+
+func BenchmarkDelimiterSetParseLongLine(b *testing.B) {
+	s := strings.Repeat(`f(a[1], {"k": 'v'}) `, 10000)
+	set := DefaultDelimiterSet()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		set.Parse(s)
+	}
+}
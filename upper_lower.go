@@ -1,6 +1,10 @@
 package gsl
 
-import "strings"
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 type stringer interface {
 	String() string
@@ -25,3 +29,117 @@ func StringerToUpperString(s stringer) string {
 func StringerToLowerString(s stringer) string {
 	return strings.ToLower(s.String())
 }
+
+// SplitWords splits identifier |s| into words, for case conversion.
+//
+// Words are separated by any runes other than letters, marks and digits,
+// and by case changes: an uppercase letter following a non-uppercase letter or a digit
+// starts a new word, and so does the last uppercase letter of an acronym
+// followed by a lowercase letter, e.g. "HTTPServer" is split into "HTTP" and "Server".
+// Letters without case, e.g. Thai or CJK characters, never start a new word.
+func SplitWords[T ~string](s T) []T {
+	runes := []rune(string(s))
+
+	var words []T
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r) {
+			if start != -1 {
+				words = append(words, T(runes[start:i]))
+				start = -1
+			}
+
+			continue
+		}
+
+		if start == -1 {
+			start = i
+			continue
+		}
+
+		if isUpperRune(r) && isWordBoundary(runes, i) {
+			words = append(words, T(runes[start:i]))
+			start = i
+		}
+	}
+
+	if start != -1 {
+		words = append(words, T(runes[start:]))
+	}
+
+	return words
+}
+
+// isWordBoundary reports whether uppercase rune runes[i] starts a new word
+func isWordBoundary(runes []rune, i int) bool {
+	// Combining marks belong to the preceding letter
+	j := i - 1
+	for j > 0 && unicode.IsMark(runes[j]) {
+		j--
+	}
+
+	if !isUpperRune(runes[j]) {
+		return true
+	}
+
+	// End of an acronym, e.g. 'S' in "HTTPServer"
+	return i+1 < len(runes) && unicode.IsLower(runes[i+1])
+}
+
+func isUpperRune(r rune) bool {
+	return unicode.IsUpper(r) || unicode.IsTitle(r)
+}
+
+// ToSnakeCase converts |s| to snake_case, e.g. "HTTPServer" to "http_server".
+// See SplitWords for how words are split.
+func ToSnakeCase[T ~string](s T) T {
+	return joinWords(s, "_", strings.ToLower, strings.ToLower)
+}
+
+// ToKebabCase converts |s| to kebab-case, e.g. "HTTPServer" to "http-server".
+func ToKebabCase[T ~string](s T) T {
+	return joinWords(s, "-", strings.ToLower, strings.ToLower)
+}
+
+// ToCamelCase converts |s| to camelCase, e.g. "http_server" to "httpServer".
+// Acronyms are not kept in uppercase, e.g. "ServeHTTP" is converted to "serveHttp".
+func ToCamelCase[T ~string](s T) T {
+	return joinWords(s, "", strings.ToLower, capitalize)
+}
+
+// ToPascalCase converts |s| to PascalCase, e.g. "http_server" to "HttpServer".
+// Acronyms are not kept in uppercase, e.g. "ServeHTTP" is converted to "ServeHttp".
+func ToPascalCase[T ~string](s T) T {
+	return joinWords(s, "", capitalize, capitalize)
+}
+
+// ToTitleCase converts |s| to space-separated Title Case,
+// e.g. "http_server" to "Http Server".
+func ToTitleCase[T ~string](s T) T {
+	return joinWords(s, " ", capitalize, capitalize)
+}
+
+// joinWords joins words of |s| with |sep|, after mapping the first word with |first|
+// and the other words with |rest|
+func joinWords[T ~string](s T, sep string, first, rest func(string) string) T {
+	var b strings.Builder
+
+	for i, word := range SplitWords(string(s)) {
+		if i == 0 {
+			b.WriteString(first(word))
+			continue
+		}
+
+		b.WriteString(sep)
+		b.WriteString(rest(word))
+	}
+
+	return T(b.String())
+}
+
+// capitalize title-cases the first rune of |word| and lowercases the rest
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToTitle(r)) + strings.ToLower(word[size:])
+}
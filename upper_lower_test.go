@@ -1,6 +1,9 @@
 package gsl
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 type stringAlias string
 
@@ -34,3 +37,93 @@ func TestToLower(t *testing.T) {
 	}
 	testUpperLower(t, tests, ToLower[stringAlias])
 }
+
+func TestCaseConversion(t *testing.T) {
+	type test struct {
+		s      string
+		words  []string
+		snake  string
+		kebab  string
+		camel  string
+		pascal string
+		title  string
+	}
+
+	tests := []test{
+		{
+			s: "", words: nil,
+		},
+		{
+			s: "HTTPServer", words: []string{"HTTP", "Server"},
+			snake: "http_server", kebab: "http-server", camel: "httpServer", pascal: "HttpServer", title: "Http Server",
+		},
+		{
+			s: "ServeHTTP", words: []string{"Serve", "HTTP"},
+			snake: "serve_http", kebab: "serve-http", camel: "serveHttp", pascal: "ServeHttp", title: "Serve Http",
+		},
+		{
+			s: "userID", words: []string{"user", "ID"},
+			snake: "user_id", kebab: "user-id", camel: "userId", pascal: "UserId", title: "User Id",
+		},
+		{
+			s: "  foo__bar-baz.qux ", words: []string{"foo", "bar", "baz", "qux"},
+			snake: "foo_bar_baz_qux", kebab: "foo-bar-baz-qux", camel: "fooBarBazQux", pascal: "FooBarBazQux", title: "Foo Bar Baz Qux",
+		},
+		{
+			s: "HTTP2Server", words: []string{"HTTP2", "Server"},
+			snake: "http2_server", kebab: "http2-server", camel: "http2Server", pascal: "Http2Server", title: "Http2 Server",
+		},
+		{
+			s: "utf8Decode v2", words: []string{"utf8", "Decode", "v2"},
+			snake: "utf8_decode_v2", kebab: "utf8-decode-v2", camel: "utf8DecodeV2", pascal: "Utf8DecodeV2", title: "Utf8 Decode V2",
+		},
+		{
+			s: "ÉcoleNormale", words: []string{"École", "Normale"},
+			snake: "école_normale", kebab: "école-normale", camel: "écoleNormale", pascal: "ÉcoleNormale", title: "École Normale",
+		},
+		{
+			s: "ΣίσυφοςΚαι", words: []string{"Σίσυφος", "Και"},
+			snake: "σίσυφος_και", kebab: "σίσυφος-και", camel: "σίσυφοςΚαι", pascal: "ΣίσυφοςΚαι", title: "Σίσυφος Και",
+		},
+		{
+			s: "สวัสดีWorld", words: []string{"สวัสดี", "World"},
+			snake: "สวัสดี_world", kebab: "สวัสดี-world", camel: "สวัสดีWorld", pascal: "สวัสดีWorld", title: "สวัสดี World",
+		},
+		{
+			s: "CAFÉBar", words: []string{"CAFÉ", "Bar"},
+			snake: "café_bar", kebab: "café-bar", camel: "caféBar", pascal: "CaféBar", title: "Café Bar",
+		},
+		{
+			s: "ǆungla_ǇUBAV", words: []string{"ǆungla", "ǇUBAV"},
+			snake: "ǆungla_ǉubav", kebab: "ǆungla-ǉubav", camel: "ǆunglaǈubav", pascal: "ǅunglaǈubav", title: "ǅungla ǈubav",
+		},
+	}
+
+	for i := range tests {
+		test := &tests[i]
+
+		if words := SplitWords(test.s); !reflect.DeepEqual(words, test.words) {
+			t.Logf("Expecting %q", test.words)
+			t.Fatalf("[%d] unexpected words for %q: %q", i, test.s, words)
+		}
+
+		results := map[string][2]string{
+			"snake":  {test.snake, ToSnakeCase(test.s)},
+			"kebab":  {test.kebab, ToKebabCase(test.s)},
+			"camel":  {test.camel, ToCamelCase(test.s)},
+			"pascal": {test.pascal, ToPascalCase(test.s)},
+			"title":  {test.title, ToTitleCase(test.s)},
+		}
+
+		for name, result := range results {
+			if result[0] != result[1] {
+				t.Logf("Expecting %q", result[0])
+				t.Fatalf("[%d] unexpected %s case for %q: %q", i, name, test.s, result[1])
+			}
+		}
+	}
+
+	if s := ToSnakeCase(stringAlias("FooBar")); s != stringAlias("foo_bar") {
+		t.Fatalf("unexpected snake case for string alias: %s", s)
+	}
+}